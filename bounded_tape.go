@@ -0,0 +1,169 @@
+package turing
+
+import "fmt"
+
+// OutOfBoundsError is the error of bounded tapes when the head reads, writes
+// or moves outside of the tape. It matches ErrOutOfBounds.
+type OutOfBoundsError struct {
+	// Pos is the position outside of the tape.
+	Pos int
+}
+
+func (e *OutOfBoundsError) Error() string {
+	return fmt.Sprintf("position %d is out of the tape bounds", e.Pos)
+}
+
+// Is tells if the target is ErrOutOfBounds.
+func (e *OutOfBoundsError) Is(target error) bool {
+	return target == ErrOutOfBounds
+}
+
+// limitedTape is a tape that limits where the head can go.
+type limitedTape interface {
+	// limit returns the position where the head goes when it moves to pos,
+	// or an error when it can't move there.
+	limit(pos int) (int, error)
+	// writable returns the error Set returns when it can't write the symbols
+	// from pos, without writing them.
+	writable(pos int, symbols ...Symbol) error
+}
+
+// boundedTape is a turing machine tape with a fixed range of cells.
+type boundedTape struct {
+	cells       []Symbol
+	first       int
+	leftMarker  Symbol
+	rightMarker Symbol
+}
+
+// NewBoundedTape creates a tape with the cells from position first to last,
+// initialized with the blank symbol (nil), like the tape of a linear bounded
+// automaton.
+//
+// When leftMarker is not nil, position first-1 has the left end marker, and
+// when rightMarker is not nil, position last+1 has the right end marker. The
+// head can read the markers, but can't move past them, and they can only be
+// overwritten with themselves. Reading, writing or moving outside of the tape
+// returns an *OutOfBoundsError.
+func NewBoundedTape(first, last int, leftMarker, rightMarker Symbol) Tape {
+	size := last - first + 1
+	if size < 0 {
+		size = 0
+	}
+	return &boundedTape{
+		cells:       make([]Symbol, size),
+		first:       first,
+		leftMarker:  leftMarker,
+		rightMarker: rightMarker,
+	}
+}
+
+// marker returns the end marker on the position, if there is one.
+func (t *boundedTape) marker(pos int) (Symbol, bool) {
+	switch {
+	case pos == t.first-1 && t.leftMarker != nil:
+		return t.leftMarker, true
+	case pos == t.first+len(t.cells) && t.rightMarker != nil:
+		return t.rightMarker, true
+	}
+	return nil, false
+}
+
+func (t *boundedTape) Get(pos int) (Symbol, error) {
+	if marker, ok := t.marker(pos); ok {
+		return marker, nil
+	}
+	i := pos - t.first
+	if i < 0 || i >= len(t.cells) {
+		return nil, &OutOfBoundsError{Pos: pos}
+	}
+	return t.cells[i], nil
+}
+
+func (t *boundedTape) Set(pos int, symbols ...Symbol) error {
+	if err := t.writable(pos, symbols...); err != nil {
+		return err
+	}
+	for i, s := range symbols {
+		if j := pos + i - t.first; j >= 0 && j < len(t.cells) {
+			t.cells[j] = s
+		}
+	}
+	return nil
+}
+
+func (t *boundedTape) writable(pos int, symbols ...Symbol) error {
+	for i, s := range symbols {
+		if marker, ok := t.marker(pos + i); ok {
+			if s != marker {
+				return fmt.Errorf("can't overwrite end marker %v on position %d with %v", marker, pos+i, s)
+			}
+			continue
+		}
+		if j := pos + i - t.first; j < 0 || j >= len(t.cells) {
+			return &OutOfBoundsError{Pos: pos + i}
+		}
+	}
+	return nil
+}
+
+func (t *boundedTape) limit(pos int) (int, error) {
+	if _, err := t.Get(pos); err != nil {
+		return 0, err
+	}
+	return pos, nil
+}
+
+// leftBoundedTape is a turing machine tape that is infinite only to the
+// right.
+type leftBoundedTape struct {
+	cells infiniteTape
+	first int
+	stay  bool
+}
+
+// NewLeftBoundedTape creates a one-way infinite tape, with the cells from
+// position first on, initialized with the blank symbol (nil).
+//
+// When stay is true, a head moving left of the first position stays on it,
+// like on textbook one-way infinite machines. Otherwise moving, reading or
+// writing left of the first position returns an *OutOfBoundsError.
+func NewLeftBoundedTape(first int, stay bool) Tape {
+	return &leftBoundedTape{first: first, stay: stay}
+}
+
+func (t *leftBoundedTape) Get(pos int) (Symbol, error) {
+	if pos < t.first {
+		return nil, &OutOfBoundsError{Pos: pos}
+	}
+	return t.cells.Get(pos - t.first)
+}
+
+func (t *leftBoundedTape) Set(pos int, symbols ...Symbol) error {
+	if err := t.writable(pos, symbols...); err != nil {
+		return err
+	}
+	return t.cells.Set(pos-t.first, symbols...)
+}
+
+func (t *leftBoundedTape) writable(pos int, symbols ...Symbol) error {
+	if pos < t.first && len(symbols) > 0 {
+		return &OutOfBoundsError{Pos: pos}
+	}
+	return nil
+}
+
+func (t *leftBoundedTape) limit(pos int) (int, error) {
+	if pos >= t.first {
+		return pos, nil
+	}
+	if t.stay {
+		return t.first, nil
+	}
+	return 0, &OutOfBoundsError{Pos: pos}
+}
+
+func (t *leftBoundedTape) extent() (min, max int, nonBlank bool) {
+	min, max, nonBlank = t.cells.extent()
+	return min + t.first, max + t.first, nonBlank
+}
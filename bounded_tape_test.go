@@ -0,0 +1,162 @@
+package turing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundedTape(t *testing.T) {
+	assertOutOfBounds := func(t *testing.T, err error, pos int) {
+		var oob *turing.OutOfBoundsError
+		if assert.True(t, errors.As(err, &oob), "error should be an *OutOfBoundsError, got %v", err) {
+			assert.Equal(t, pos, oob.Pos)
+		}
+	}
+
+	t.Run("SetAndGet", func(t *testing.T) {
+		t.Log("should only set and get the cells between the markers")
+
+		tape := turing.NewBoundedTape(2, 5, "<", ">")
+		assert.NoError(t, tape.Set(2, 0, 1, 0, 1))
+		assert.NoError(t, tape.Set(1, "<", "a"))
+		assert.NoError(t, tape.Set(6, ">"))
+		for pos, expected := range map[int]turing.Symbol{1: "<", 2: "a", 3: 1, 4: 0, 5: 1, 6: ">"} {
+			symbol, err := tape.Get(pos)
+			if assert.NoError(t, err, pos) {
+				assert.Equal(t, expected, symbol, pos)
+			}
+		}
+
+		assert.EqualError(t, tape.Set(6, 1), "can't overwrite end marker > on position 6 with 1")
+		assert.EqualError(t, tape.Set(4, 1, 1, 1), "can't overwrite end marker > on position 6 with 1")
+		symbol, _ := tape.Get(4)
+		assert.Equal(t, 0, symbol, "should not write when a cell can't be written")
+
+		_, err := tape.Get(0)
+		assertOutOfBounds(t, err, 0)
+		_, err = tape.Get(7)
+		assertOutOfBounds(t, err, 7)
+		assertOutOfBounds(t, tape.Set(7, 1), 7)
+		assert.EqualError(t, tape.Set(7, 1), "position 7 is out of the tape bounds")
+	})
+
+	t.Run("NoMarkers", func(t *testing.T) {
+		t.Log("should not go past the cells without markers")
+
+		tape := turing.NewBoundedTape(0, 1, nil, nil)
+		head := turing.Head{}
+		head.Attach(tape, 0)
+		head.Move(turing.LEFT)
+		assert.Equal(t, 0, head.Pos())
+		head.Move(turing.RIGHT)
+		head.Move(turing.RIGHT)
+		assert.Equal(t, 1, head.Pos())
+		assert.Equal(t, 1, head.MaxPos())
+	})
+
+	t.Run("LinearBoundedAutomaton", func(t *testing.T) {
+		t.Log("should run a machine between the end markers")
+
+		invert := turing.State{"invert", false}
+		back := turing.State{"back", false}
+		halt := turing.State{"halt", true}
+		program := turing.Program{}
+		program.AddOp(turing.Op{invert, 0, 1, turing.RIGHT, invert})
+		program.AddOp(turing.Op{invert, 1, 0, turing.RIGHT, invert})
+		program.AddOp(turing.Op{invert, ">", turing.KEEP, turing.LEFT, back})
+		program.AddOp(turing.Op{back, "<", turing.KEEP, turing.RIGHT, halt})
+		program.AddOp(turing.Op{back, turing.ANY, turing.KEEP, turing.LEFT, back})
+
+		tape := turing.NewBoundedTape(0, 3, "<", ">")
+		tape.Set(0, 0, 1, 1, 0)
+		head := turing.Head{}
+		head.Attach(tape, 0)
+		machine := turing.Machine{Head: &head, Program: &program, State: invert}
+		if assert.NoError(t, machine.Run()) {
+			assert.Equal(t, " -1: <\n[0: 1]\n 1: 0\n 2: 0\n 3: 1\n 4: >\n", head.PrintTape(-1, 4))
+			assert.Equal(t, 10, machine.Steps())
+		}
+	})
+
+	t.Run("RunOutOfBounds", func(t *testing.T) {
+		t.Log("should stop runs that move past the end marker, without changing the machine")
+
+		state := turing.State{"state", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, turing.ANY, 1, turing.RIGHT, state})
+
+		tape := turing.NewBoundedTape(0, 2, nil, ">")
+		head := turing.Head{}
+		head.Attach(tape, 0)
+		machine := turing.Machine{Head: &head, Program: &program, State: state}
+		result, err := machine.RunContext(context.Background(), 100)
+		assertOutOfBounds(t, err, 4)
+		assert.Equal(t, turing.RunResult{Steps: 3, Reason: turing.OutOfBounds}, result)
+		assert.Equal(t, 3, head.Pos())
+		assert.Equal(t, " 0: 1\n 1: 1\n 2: 1\n[3: >]\n", head.PrintTape(0, 3))
+
+		program = turing.Program{}
+		program.AddOp(turing.Op{state, turing.ANY, 1, turing.STAY, state})
+		result, err = machine.RunContext(context.Background(), 100)
+		assert.EqualError(t, err, "can't overwrite end marker > on position 3 with 1")
+		assert.Equal(t, turing.RunResult{Steps: 0, Reason: turing.TapeError}, result)
+	})
+}
+
+func TestLeftBoundedTape(t *testing.T) {
+	left := turing.State{"left", false}
+	program := turing.Program{}
+	program.AddOp(turing.Op{left, turing.ANY, "x", turing.LEFT, left})
+
+	t.Run("Stay", func(t *testing.T) {
+		t.Log("should keep the head on the first cell when it moves left of it")
+
+		head := turing.Head{}
+		head.Attach(turing.NewLeftBoundedTape(0, true), 2)
+		machine := turing.Machine{Head: &head, Program: &program, State: left}
+		result, err := machine.RunContext(context.Background(), 10)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.BudgetExhausted, result.Reason)
+			assert.Equal(t, "[0: x]\n 1: x\n 2: x\n 3: <nil>\n", head.PrintTape(0, 3))
+			assert.Equal(t, 0, head.MinPos())
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Log("should stop the machine when the head moves left of the first cell")
+
+		tape := turing.NewLeftBoundedTape(-1, false)
+		head := turing.Head{}
+		head.Attach(tape, 1)
+		machine := turing.Machine{Head: &head, Program: &program, State: left}
+		result, err := machine.RunContext(context.Background(), 10)
+		assert.EqualError(t, err, "position -2 is out of the tape bounds")
+		assert.Equal(t, turing.RunResult{Steps: 2, Reason: turing.OutOfBounds}, result)
+		assert.Equal(t, "[-1: <nil>]\n 0: x\n 1: x\n", head.PrintTape(-1, 1))
+
+		_, err = tape.Get(-2)
+		assert.EqualError(t, err, "position -2 is out of the tape bounds")
+		assert.EqualError(t, tape.Set(-5, 1, 2, 3, 4), "position -5 is out of the tape bounds")
+	})
+
+	t.Run("MultiMachine", func(t *testing.T) {
+		t.Log("should not change any tape when a head can't move")
+
+		state := turing.State{"state", false}
+		program := turing.MultiProgram{}
+		program.AddOp(turing.MultiOp{state, []turing.Symbol{nil, nil}, []turing.Symbol{1, 1}, []string{turing.RIGHT, turing.LEFT}, state})
+
+		head1 := turing.Head{}
+		head1.Attach(turing.NewInfiniteTape(), 0)
+		head2 := turing.Head{}
+		head2.Attach(turing.NewLeftBoundedTape(0, false), 0)
+		machine := turing.MultiMachine{Heads: []*turing.Head{&head1, &head2}, Program: &program, State: state}
+		assert.EqualError(t, machine.Step(), "position -1 is out of the tape bounds")
+		assert.Equal(t, "[0: <nil>]\n", head1.PrintTape(0, 0))
+		assert.Equal(t, "[0: <nil>]\n", head2.PrintTape(0, 0))
+	})
+}
@@ -0,0 +1,229 @@
+// Command turing runs a machine definition file.
+//
+// Usage:
+//
+//	turing [flags] file [tape]
+//
+// The definition format is chosen by the file extension: .json, .tm or .txt
+// for the text format, .morphett and .yaml or .yml for turingmachine.io. The
+// tape argument replaces the definition tape, and "-" reads it from the
+// standard input. Tapes with spaces have one symbol per word, otherwise one
+// symbol per character, and _ is the blank symbol.
+//
+// With the -debug flag, the machine runs on the interactive debugger of
+// package debugger, reading its commands from the standard input. With the
+// -profile flag, the execution profile is written to the standard error
+// when the machine stops.
+//
+// When the machine stops, it prints the final state, the number of steps,
+// the head position and the tape walked by the head. The exit code is 0 when
+// the machine halts, 1 for usage or definition errors, 2 when there is no
+// operation to execute, 3 when the step limit is reached, 4 when it is
+// interrupted, 5 when the -loops flag is set and the machine is on a cycle
+// that never halts and 6 when the tape fails or the step fails for any other
+// reason. When debugging, the exit code is the one of the last stop of the
+// machine, and 7 when the debugger paused it, on a breakpoint, a watchpoint,
+// a state or after the requested steps.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/massahud/turing"
+	"github.com/massahud/turing/debugger"
+)
+
+const (
+	exitHalted = iota
+	exitUsage
+	exitNoOperation
+	exitBudget
+	exitCancelled
+	exitLooping
+	exitTapeError
+	exitPaused
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns its exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("turing", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	steps := flags.Int("steps", 1000000, "maximum number of steps, 0 for no limit")
+	format := flags.String("format", "", "definition format: json, text, morphett or yaml, by default the file extension")
+	trace := flags.Bool("trace", false, "write every step to the standard error")
+	profile := flags.Bool("profile", false, "write the execution profile to the standard error when the machine stops")
+	loops := flags.Bool("loops", false, "stop when the machine is on a cycle and will never halt")
+	debug := flags.Bool("debug", false, "debug the machine, reading the debugger commands from the standard input")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: turing [flags] file [tape]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	def, err := turing.LoadDefinition(flags.Arg(0), *format)
+	if err != nil {
+		if errors.Is(err, turing.ErrUnknownFormat) {
+			fmt.Fprintf(stderr, "turing: %s, use the -format flag\n", err.Error())
+			return exitUsage
+		}
+		fmt.Fprintf(stderr, "turing: %s\n", err.Error())
+		return exitUsage
+	}
+
+	if flags.NArg() == 2 {
+		text := flags.Arg(1)
+		if text == "-" && *debug {
+			fmt.Fprintln(stderr, "turing: the tape can't be read from the standard input when debugging")
+			return exitUsage
+		}
+		if text == "-" {
+			data, err := ioutil.ReadAll(stdin)
+			if err != nil {
+				fmt.Fprintf(stderr, "turing: reading tape: %s\n", err.Error())
+				return exitUsage
+			}
+			text = string(data)
+		}
+		def.Tape = parseTape(text, def.Program)
+	}
+
+	machine := def.NewMachine()
+	observers := turing.Observers{}
+	if *trace {
+		observers = append(observers, turing.NewTraceWriter(stderr))
+	}
+	var profiler *turing.Profiler
+	if *profile {
+		profiler = turing.NewProfiler(machine)
+		observers = append(observers, profiler)
+	}
+	if len(observers) > 0 {
+		machine.Observer = observers
+	}
+	if *loops {
+		machine.LoopDetector = turing.NewLoopDetector()
+	}
+	if *debug {
+		d := debugger.New(machine)
+		d.MaxSteps = *steps
+		if err := d.Run(stdin, stdout); err != nil {
+			fmt.Fprintf(stderr, "turing: %s\n", err.Error())
+			return exitUsage
+		}
+		if machine.State.Halt {
+			return exitHalted
+		}
+		if stop := d.LastStop(); stop.Reason == debugger.MachineStopped {
+			return exitCode(stop.Run)
+		}
+		return exitPaused
+	}
+	result, err := machine.RunContext(ctx, *steps)
+
+	from, to := machine.Head.MinPos(), machine.Head.MaxPos()
+	if len(def.Tape) > 0 {
+		if def.TapeOffset < from {
+			from = def.TapeOffset
+		}
+		if last := def.TapeOffset + len(def.Tape) - 1; last > to {
+			to = last
+		}
+	}
+	fmt.Fprintf(stdout, "state: %v\nsteps: %d\nposition: %d\n", machine.State, machine.Steps(), machine.Head.Pos())
+	fmt.Fprint(stdout, machine.Head.PrintTape(from, to))
+	if profiler != nil {
+		profiler.Profile().WriteTable(stderr)
+	}
+
+	switch result.Reason {
+	case turing.BudgetExhausted:
+		fmt.Fprintf(stderr, "turing: step limit of %d reached\n", *steps)
+	case turing.Looping:
+		fmt.Fprintf(stderr, "turing: machine is looping, %v\n", result.Cycle)
+	case turing.NoOperation, turing.Cancelled, turing.OutOfBounds, turing.TapeError, turing.StepFailed:
+		fmt.Fprintf(stderr, "turing: %s\n", err.Error())
+	}
+	return exitCode(result.Reason)
+}
+
+// exitCode returns the exit code of a run that stopped for the reason.
+func exitCode(reason turing.StopReason) int {
+	switch reason {
+	case turing.Halted:
+		return exitHalted
+	case turing.NoOperation:
+		return exitNoOperation
+	case turing.BudgetExhausted:
+		return exitBudget
+	case turing.Cancelled:
+		return exitCancelled
+	case turing.Looping:
+		return exitLooping
+	}
+	return exitTapeError
+}
+
+// parseTape converts the text to tape symbols. Each word, or character when
+// there are no spaces, is converted to the symbol the program uses: the
+// string itself or its integer value.
+func parseTape(text string, program *turing.Program) []turing.Symbol {
+	text = strings.TrimSpace(text)
+	var words []string
+	if strings.IndexFunc(text, unicode.IsSpace) >= 0 {
+		words = strings.Fields(text)
+	} else {
+		for _, r := range text {
+			words = append(words, string(r))
+		}
+	}
+
+	alphabet := make(map[turing.Symbol]bool)
+	if program != nil {
+		for _, op := range program.ListOps() {
+			alphabet[op.Symbol] = true
+			alphabet[op.WriteSymbol] = true
+		}
+	}
+
+	tape := make([]turing.Symbol, len(words))
+	for i, word := range words {
+		if word == "_" {
+			continue
+		}
+		n, err := strconv.Atoi(word)
+		if err == nil && (alphabet[n] || !alphabet[word]) {
+			tape[i] = n
+			continue
+		}
+		tape[i] = word
+	}
+	return tape
+}
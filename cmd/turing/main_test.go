@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			name: "Halt",
+			args: []string{"../../testdata/separate01.tm", "0 1 1"},
+			code: exitHalted,
+			stdout: "state: [halt]\nsteps: 6\nposition: 1\n" +
+				" 0: 0\n[1: 1]\n 2: 1\n 3: <nil>\n",
+		},
+		{
+			name:   "Stdin",
+			args:   []string{"../../testdata/binary_increment.yaml", "-"},
+			stdin:  "11\n",
+			code:   exitHalted,
+			stdout: "state: [done]\nsteps: 6\nposition: -2\n[-2: <nil>]\n -1: 1\n 0: 0\n 1: 0\n 2: <nil>\n",
+		},
+		{
+			name:   "NoOperation",
+			args:   []string{"../../testdata/binary_increment.yaml", "1x"},
+			code:   exitNoOperation,
+			stdout: "state: right\nsteps: 1\nposition: 1\n 0: 1\n[1: x]\n",
+			stderr: "turing: no operation for state right and symbol x\n",
+		},
+		{
+			name:   "Budget",
+			args:   []string{"-steps", "2", "../../testdata/separate01.json"},
+			code:   exitBudget,
+			stderr: "turing: step limit of 2 reached\n",
+		},
+		{
+			name:   "Debug",
+			args:   []string{"-debug", "../../testdata/separate01.tm", "1 1 0"},
+			stdin:  "break state back0\ncontinue\ntape 1\n",
+			code:   exitPaused,
+			stdout: "(turing) breakpoint 1: state back0\n(turing) breakpoint 1: state back0\nstep 3: state back0, position 1, symbol 1\n(turing)  0: <nil>\n[1: 1]\n 2: 1\n(turing) \n",
+		},
+		{
+			name:   "DebugHalt",
+			args:   []string{"-debug", "../../testdata/separate01.tm", "0 1 1"},
+			stdin:  "continue\nquit\n",
+			code:   exitHalted,
+			stdout: "(turing) machine is halted\nstep 6: state [halt], position 1, symbol 1\n(turing) ",
+		},
+		{
+			name:   "DebugLoops",
+			args:   []string{"-debug", "-loops", "../../testdata/forever.tm"},
+			stdin:  "continue\n",
+			code:   exitLooping,
+			stdout: "(turing) machine is looping, configuration of step 1 repeats every 1 steps, moved 1 positions\nstep 2: state write, position 2, symbol <nil>\n(turing) \n",
+		},
+		{
+			name:   "DebugStdinTape",
+			args:   []string{"-debug", "../../testdata/separate01.tm", "-"},
+			code:   exitUsage,
+			stderr: "turing: the tape can't be read from the standard input when debugging\n",
+		},
+		{
+			name:   "Loops",
+			args:   []string{"-loops", "../../testdata/forever.tm"},
+			code:   exitLooping,
+			stdout: "state: write\nsteps: 2\nposition: 2\n 0: 1\n 1: 1\n[2: <nil>]\n",
+			stderr: "turing: machine is looping, configuration of step 1 repeats every 1 steps, moved 1 positions\n",
+		},
+		{
+			name:   "UnknownFormat",
+			args:   []string{"definition.bin"},
+			code:   exitUsage,
+			stderr: "turing: unknown definition format for definition.bin, use the -format flag\n",
+		},
+		{
+			name:   "MissingFile",
+			args:   []string{"-format", "text", "missing"},
+			code:   exitUsage,
+			stderr: "turing: open missing: no such file or directory\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("should exit with code %d", tt.code)
+
+			stdout := strings.Builder{}
+			stderr := strings.Builder{}
+			code := run(context.Background(), tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.stderr, stderr.String())
+			if tt.stdout != "" {
+				assert.Equal(t, tt.stdout, stdout.String())
+			}
+		})
+	}
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Log("should stop when the context is cancelled")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		stderr := strings.Builder{}
+		code := run(ctx, []string{"../../testdata/separate01.json"}, strings.NewReader(""), &strings.Builder{}, &stderr)
+		assert.Equal(t, exitCancelled, code)
+		assert.Equal(t, "turing: context canceled\n", stderr.String())
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		t.Log("should write the execution profile to the standard error")
+
+		stderr := strings.Builder{}
+		code := run(context.Background(), []string{"-profile", "../../testdata/separate01.tm", "0 1 1"}, strings.NewReader(""), &strings.Builder{}, &stderr)
+		assert.Equal(t, exitHalted, code)
+		assert.Contains(t, stderr.String(), "steps            6\n")
+		assert.Contains(t, stderr.String(), "get0   1/1,R  get0    1      16.7%\n")
+	})
+}
+
+func TestParseTape(t *testing.T) {
+	a := turing.State{Name: "a"}
+	stringProgram := turing.Program{}
+	stringProgram.AddOp(turing.Op{State: a, Symbol: "1", WriteSymbol: "0", Movement: turing.RIGHT, NextState: a})
+	intProgram := turing.Program{}
+	intProgram.AddOp(turing.Op{State: a, Symbol: 1, WriteSymbol: 0, Movement: turing.RIGHT, NextState: a})
+
+	t.Run("Alphabet", func(t *testing.T) {
+		t.Log("should convert the symbols to the ones used by the program")
+
+		assert.Equal(t, []turing.Symbol{"1", nil, "0", 2, "a"}, parseTape("1_02a", &stringProgram))
+		assert.Equal(t, []turing.Symbol{1, nil, 0, 2, "a"}, parseTape("1_02a", &intProgram))
+	})
+
+	t.Run("Words", func(t *testing.T) {
+		t.Log("should read one symbol per word when there are spaces")
+
+		assert.Equal(t, []turing.Symbol{10, nil, "abc"}, parseTape(" 10 _ abc\n", &intProgram))
+	})
+}
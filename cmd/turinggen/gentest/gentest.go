@@ -0,0 +1,7 @@
+// Package gentest has code generated by turinggen from the testdata
+// definitions, tested against the interpreted machines.
+package gentest
+
+//go:generate go run .. -o separate01.go ../../../testdata/separate01.tm
+//go:generate go run .. -o binary_increment.go ../../../testdata/binary_increment.yaml
+//go:generate go run .. -o forever.go ../../../testdata/forever.tm
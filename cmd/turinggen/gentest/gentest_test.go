@@ -0,0 +1,199 @@
+package gentest_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/massahud/turing/cmd/turinggen/gentest"
+	"github.com/stretchr/testify/assert"
+)
+
+// generated is a machine of the generated code.
+type generated struct {
+	run    func(maxSteps int) error
+	state  func() string
+	halted func() bool
+	pos    func() int
+	steps  func() int
+	symbol func(pos int) interface{}
+}
+
+var definitions = []struct {
+	name   string
+	path   string
+	source string
+	new    func(tape []interface{}, offset, pos int) (generated, error)
+	tapes  [][]interface{}
+}{
+	{
+		name:   "Separate01",
+		path:   "../../../testdata/separate01.tm",
+		source: "separate01.go",
+		new: func(tape []interface{}, offset, pos int) (generated, error) {
+			m, err := gentest.NewSeparate01Machine(tape, offset, pos)
+			if err != nil {
+				return generated{}, err
+			}
+			return generated{
+				run:    m.Run,
+				state:  func() string { return gentest.Separate01States[m.State] },
+				halted: m.Halted,
+				pos:    func() int { return m.Pos },
+				steps:  func() int { return m.Steps },
+				symbol: m.Symbol,
+			}, nil
+		},
+		tapes: [][]interface{}{
+			{},
+			{0, 1, 0, 1, 0, 1, 1, 1, 0, 1},
+			{1, 1, 1, 0, 0, 0},
+			{1, 0, 1, nil, 1, 0},
+		},
+	},
+	{
+		name:   "BinaryIncrement",
+		path:   "../../../testdata/binary_increment.yaml",
+		source: "binary_increment.go",
+		new: func(tape []interface{}, offset, pos int) (generated, error) {
+			m, err := gentest.NewBinaryIncrementMachine(tape, offset, pos)
+			if err != nil {
+				return generated{}, err
+			}
+			return generated{
+				run:    m.Run,
+				state:  func() string { return gentest.BinaryIncrementStates[m.State] },
+				halted: m.Halted,
+				pos:    func() int { return m.Pos },
+				steps:  func() int { return m.Steps },
+				symbol: m.Symbol,
+			}, nil
+		},
+		tapes: [][]interface{}{
+			{},
+			{"1", "0", "1", "1"},
+			{"1", "1", "1", "1", "1"},
+			{"0", nil, "1"},
+		},
+	},
+	{
+		name:   "Forever",
+		path:   "../../../testdata/forever.tm",
+		source: "forever.go",
+		new: func(tape []interface{}, offset, pos int) (generated, error) {
+			m, err := gentest.NewForeverMachine(tape, offset, pos)
+			if err != nil {
+				return generated{}, err
+			}
+			return generated{
+				run:    m.Run,
+				state:  func() string { return gentest.ForeverStates[m.State] },
+				halted: m.Halted,
+				pos:    func() int { return m.Pos },
+				steps:  func() int { return m.Steps },
+				symbol: m.Symbol,
+			}, nil
+		},
+		tapes: [][]interface{}{
+			{},
+			{1, nil, 1},
+		},
+	},
+}
+
+func TestGenerated(t *testing.T) {
+	t.Run("Interpreter", func(t *testing.T) {
+		t.Log("should run like the interpreted machine")
+
+		for _, d := range definitions {
+			def, err := turing.LoadDefinition(d.path, "")
+			if !assert.NoError(t, err, d.name) {
+				continue
+			}
+			for i, tape := range d.tapes {
+				for _, offset := range []int{0, -7, 3} {
+					for _, head := range []int{0, 1, len(tape) - 1} {
+						name := fmt.Sprintf("%s tape %d offset %d head %d", d.name, i, offset, head)
+						def.Tape = make([]turing.Symbol, len(tape))
+						for i, s := range tape {
+							def.Tape[i] = s
+						}
+						def.TapeOffset, def.HeadPos = offset, offset+head
+
+						machine := def.NewMachine()
+						result, err := machine.RunContext(context.Background(), 1000)
+						expectedErr := ""
+						if err != nil {
+							expectedErr = fmt.Sprintf("Error at instruction %d: %s", result.Steps+1, err.Error())
+						}
+
+						m, err := d.new(tape, offset, offset+head)
+						if !assert.NoError(t, err, name) {
+							continue
+						}
+						err = m.run(1000)
+						actualErr := ""
+						if err != nil {
+							actualErr = err.Error()
+						}
+
+						assert.Equal(t, expectedErr, actualErr, name)
+						assert.Equal(t, machine.State.Name, m.state(), name)
+						assert.Equal(t, machine.State.Halt, m.halted(), name)
+						assert.Equal(t, machine.Steps(), m.steps(), name)
+						assert.Equal(t, machine.Head.Pos(), m.pos(), name)
+						snapshot, _ := machine.Snapshot()
+						for i, symbol := range snapshot.Tape.Cells {
+							pos := i - snapshot.Tape.Shift
+							assert.Equal(t, symbol, m.symbol(pos), "%s: position %d", name, pos)
+						}
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Log("should resume a run stopped by the step limit")
+
+		m, err := gentest.NewForeverMachine(nil, 0, 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, m.Run(3))
+		assert.NoError(t, m.Run(4))
+		assert.Equal(t, 7, m.Steps)
+		assert.Equal(t, 7, m.Pos)
+		assert.False(t, m.Halted())
+	})
+
+	t.Run("UnknownSymbol", func(t *testing.T) {
+		t.Log("should not create machines with symbols the program does not use")
+
+		_, err := gentest.NewSeparate01Machine([]interface{}{0, 2}, 0, 0)
+		assert.EqualError(t, err, "symbol 2 is not used by the program")
+	})
+
+	t.Run("UpToDate", func(t *testing.T) {
+		t.Log("should have the generated code of the current definitions")
+
+		for _, d := range definitions {
+			def, err := turing.LoadDefinition(d.path, "")
+			if !assert.NoError(t, err, d.name) {
+				continue
+			}
+			expected := bytes.Buffer{}
+			opts := turing.GoOptions{Package: "gentest", Name: d.name, Source: d.path[len("../../../testdata/"):]}
+			if !assert.NoError(t, turing.WriteGo(&expected, def, opts), d.name) {
+				continue
+			}
+			actual, err := ioutil.ReadFile(d.source)
+			if assert.NoError(t, err, d.name) {
+				assert.Equal(t, expected.String(), string(actual), "%s: run go generate", d.name)
+			}
+		}
+	})
+}
@@ -0,0 +1,109 @@
+// Command turinggen generates Go source code that runs a machine definition
+// file, with a switch per state and symbol.
+//
+// Usage:
+//
+//	turinggen [flags] file
+//
+// The definition format is chosen by the file extension, as on the turing
+// command. The generated identifiers are prefixed by the -name flag, by
+// default the file name in camel case, and the package is the one of the
+// -package flag, or of the GOPACKAGE environment variable when run by go
+// generate:
+//
+//	//go:generate go run github.com/massahud/turing/cmd/turinggen -o separate01.go separate01.tm
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/massahud/turing"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns its exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("turinggen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	pkg := flags.String("package", os.Getenv("GOPACKAGE"), "package of the generated file, main when not set")
+	name := flags.String("name", "", "prefix of the generated identifiers, by default the file name in camel case")
+	format := flags.String("format", "", "definition format: json, text, morphett or yaml, by default the file extension")
+	output := flags.String("o", "", "output file, by default the standard output")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: turinggen [flags] file")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	path := flags.Arg(0)
+	def, err := turing.LoadDefinition(path, *format)
+	if err != nil {
+		if errors.Is(err, turing.ErrUnknownFormat) {
+			fmt.Fprintf(stderr, "turinggen: %s, use the -format flag\n", err.Error())
+			return 1
+		}
+		fmt.Fprintf(stderr, "turinggen: %s\n", err.Error())
+		return 1
+	}
+	if *name == "" {
+		*name = camelCase(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	}
+
+	source := bytes.Buffer{}
+	opts := turing.GoOptions{Package: *pkg, Name: *name, Source: filepath.Base(path)}
+	if err := turing.WriteGo(&source, def, opts); err != nil {
+		fmt.Fprintf(stderr, "turinggen: %s\n", err.Error())
+		return 1
+	}
+
+	if *output == "" {
+		_, err = source.WriteTo(stdout)
+	} else {
+		err = ioutil.WriteFile(*output, source.Bytes(), 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "turinggen: %s\n", err.Error())
+		return 1
+	}
+	return 0
+}
+
+// camelCase converts a file name to an exported identifier, dropping the
+// characters that are not letters or digits and capitalizing the next ones.
+func camelCase(text string) string {
+	builder := strings.Builder{}
+	upper := true
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if builder.Len() == 0 && unicode.IsDigit(r) {
+			builder.WriteRune('P')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("Generate", func(t *testing.T) {
+		t.Log("should write the generated code to the standard output")
+
+		stdout := strings.Builder{}
+		stderr := strings.Builder{}
+		code := run([]string{"-package", "beaver", "../../testdata/separate01.json"}, &stdout, &stderr)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "", stderr.String())
+		assert.True(t, strings.HasPrefix(stdout.String(), "// Code generated by turinggen from separate01.json. DO NOT EDIT.\n\npackage beaver\n"))
+		assert.Contains(t, stdout.String(), "func NewSeparate01Machine(")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Log("should exit with code 1 on usage and generation errors")
+
+		tests := []struct {
+			args   []string
+			stderr string
+		}{
+			{[]string{"-name", "lower", "../../testdata/forever.tm"}, "turinggen: name \"lower\" is not an exported identifier\n"},
+			{[]string{"missing.tm"}, "turinggen: open missing.tm: no such file or directory\n"},
+		}
+		for _, tt := range tests {
+			stderr := strings.Builder{}
+			code := run(tt.args, &strings.Builder{}, &stderr)
+			assert.Equal(t, 1, code, tt.args)
+			assert.Equal(t, tt.stderr, stderr.String(), tt.args)
+		}
+	})
+}
+
+func TestCamelCase(t *testing.T) {
+	t.Log("should convert file names to exported identifiers")
+
+	for text, expected := range map[string]string{
+		"separate01":       "Separate01",
+		"binary_increment": "BinaryIncrement",
+		"busy-beaver.4":    "BusyBeaver4",
+		"3states":          "P3states",
+	} {
+		assert.Equal(t, expected, camelCase(text), text)
+	}
+}
@@ -0,0 +1,367 @@
+package turing
+
+import (
+	"context"
+	"fmt"
+)
+
+// CompiledProgram is a Program with its states and symbols interned into
+// small integers, and a flat transition table with one entry for each state
+// and symbol, ANY operations already applied. CompiledMachines run it without
+// map lookups.
+type CompiledProgram struct {
+	states   []State
+	stateIDs map[State]int
+	// symbols are the symbols of the program, the blank symbol is 0
+	symbols   []Symbol
+	symbolIDs map[Symbol]int
+	// table has the operations of state s on row s*len(symbols)
+	table []compiledOp
+	// anyOps are the ANY operations of each state, for symbols that are not
+	// used by the program
+	anyOps []compiledOp
+	// hasOps tells which states have operations
+	hasOps []bool
+}
+
+// compiledOp is an operation of a compiled program.
+type compiledOp struct {
+	defined bool
+	// write is the symbol written, keepSymbol to keep it
+	write int
+	move  int
+	next  int
+}
+
+// keepSymbol is the compiled write symbol of KEEP.
+const keepSymbol = -1
+
+// Compile interns the states and symbols of the program and builds its
+// transition table.
+func Compile(p *Program) *CompiledProgram {
+	c := &CompiledProgram{
+		stateIDs:  make(map[State]int),
+		symbols:   []Symbol{nil},
+		symbolIDs: map[Symbol]int{nil: 0},
+	}
+	ops := p.SortedOps()
+	for _, op := range ops {
+		c.stateID(op.State)
+		c.stateID(op.NextState)
+		if op.Symbol != ANY {
+			c.symbolID(op.Symbol)
+		}
+		if op.WriteSymbol != KEEP {
+			c.symbolID(op.WriteSymbol)
+		}
+	}
+
+	c.table = make([]compiledOp, len(c.states)*len(c.symbols))
+	c.anyOps = make([]compiledOp, len(c.states))
+	c.hasOps = make([]bool, len(c.states))
+	for _, op := range ops {
+		state := c.stateIDs[op.State]
+		compiled := compiledOp{defined: true, write: keepSymbol, next: c.stateIDs[op.NextState]}
+		if op.WriteSymbol != KEEP {
+			compiled.write = c.symbolIDs[op.WriteSymbol]
+		}
+		switch op.Movement {
+		case LEFT:
+			compiled.move = -1
+		case RIGHT:
+			compiled.move = 1
+		}
+
+		c.hasOps[state] = true
+		if op.Symbol == ANY {
+			c.anyOps[state] = compiled
+			continue
+		}
+		c.table[state*len(c.symbols)+c.symbolIDs[op.Symbol]] = compiled
+	}
+	for state, anyOp := range c.anyOps {
+		row := c.table[state*len(c.symbols) : (state+1)*len(c.symbols)]
+		for i := range row {
+			if !row[i].defined {
+				row[i] = anyOp
+			}
+		}
+	}
+	return c
+}
+
+func (c *CompiledProgram) stateID(s State) int {
+	id, ok := c.stateIDs[s]
+	if !ok {
+		id = len(c.states)
+		c.states = append(c.states, s)
+		c.stateIDs[s] = id
+	}
+	return id
+}
+
+func (c *CompiledProgram) symbolID(s Symbol) int {
+	id, ok := c.symbolIDs[s]
+	if !ok {
+		id = len(c.symbols)
+		c.symbols = append(c.symbols, s)
+		c.symbolIDs[s] = id
+	}
+	return id
+}
+
+// find returns the operation for the state and symbol.
+func (c *CompiledProgram) find(state, symbol int) compiledOp {
+	if state >= len(c.anyOps) {
+		return compiledOp{}
+	}
+	if symbol >= len(c.symbols) {
+		return c.anyOps[state]
+	}
+	return c.table[state*len(c.symbols)+symbol]
+}
+
+// CompiledMachine runs a CompiledProgram over a tape of interned symbols.
+// It gives the same results as a Machine with the same program and
+// configuration, but it has no Observer or LoopDetector.
+//
+// Snapshot and Load move configurations between compiled and interpreted
+// machines.
+type CompiledMachine struct {
+	program *CompiledProgram
+	// states and symbols are the ones of the program, followed by the ones
+	// only used by the machine configuration
+	states    []State
+	symbols   []Symbol
+	symbolIDs map[Symbol]int
+
+	state  int
+	steps  int
+	pos    int
+	minPos int
+	maxPos int
+	tape   compiledTape
+}
+
+// compiledTape is an infinite tape of interned symbols, growing like the
+// tapes created by NewInfiniteTape.
+type compiledTape struct {
+	right []int
+	left  []int
+}
+
+func (t *compiledTape) get(pos int) int {
+	if pos >= 0 {
+		if pos < len(t.right) {
+			return t.right[pos]
+		}
+		return 0
+	}
+	if i := -pos - 1; i < len(t.left) {
+		return t.left[i]
+	}
+	return 0
+}
+
+func (t *compiledTape) set(pos int, symbol int) {
+	if pos >= 0 {
+		for len(t.right) <= pos {
+			t.right = append(t.right, 0)
+		}
+		t.right[pos] = symbol
+		return
+	}
+	i := -pos - 1
+	for len(t.left) <= i {
+		t.left = append(t.left, 0)
+	}
+	t.left[i] = symbol
+}
+
+// Load creates a machine for the program on the configuration of the
+// snapshot, which can be taken from a Machine.
+func (c *CompiledProgram) Load(s Snapshot) (*CompiledMachine, error) {
+	if s.Tape.Kind != infiniteTapeKind && s.Tape.Kind != sparseTapeKind {
+		return nil, fmt.Errorf("unknown tape kind %q", s.Tape.Kind)
+	}
+	m := &CompiledMachine{
+		program:   c,
+		states:    c.states,
+		symbols:   c.symbols,
+		symbolIDs: c.symbolIDs,
+		steps:     s.Steps,
+		pos:       s.Pos,
+		minPos:    s.MinPos,
+		maxPos:    s.MaxPos,
+	}
+	m.state = m.stateID(s.State)
+	for i, cell := range s.Tape.Cells {
+		m.tape.set(i-s.Tape.Shift, m.symbolID(cell))
+	}
+	return m, nil
+}
+
+// NewCompiledMachine compiles the definition program and creates a machine
+// for it, like NewMachine.
+func (d *Definition) NewCompiledMachine() *CompiledMachine {
+	snapshot, _ := d.NewMachine().Snapshot()
+	m, _ := Compile(d.Program).Load(snapshot)
+	return m
+}
+
+// stateID interns a state of the machine configuration, copying the program
+// states before adding a new one.
+func (m *CompiledMachine) stateID(s State) int {
+	if id, ok := m.program.stateIDs[s]; ok {
+		return id
+	}
+	for id := len(m.program.states); id < len(m.states); id++ {
+		if m.states[id] == s {
+			return id
+		}
+	}
+	m.states = append(m.states[:len(m.states):len(m.states)], s)
+	return len(m.states) - 1
+}
+
+// symbolID interns a symbol of the machine tape, copying the program symbols
+// before adding a new one.
+func (m *CompiledMachine) symbolID(s Symbol) int {
+	if id, ok := m.symbolIDs[s]; ok {
+		return id
+	}
+	if len(m.symbols) == len(m.program.symbols) {
+		m.symbolIDs = make(map[Symbol]int, len(m.program.symbolIDs)+1)
+		for symbol, id := range m.program.symbolIDs {
+			m.symbolIDs[symbol] = id
+		}
+	}
+	m.symbols = append(m.symbols[:len(m.symbols):len(m.symbols)], s)
+	m.symbolIDs[s] = len(m.symbols) - 1
+	return len(m.symbols) - 1
+}
+
+// State returns the machine state.
+func (m *CompiledMachine) State() State {
+	return m.states[m.state]
+}
+
+// Steps returns the number of steps the machine executed.
+func (m *CompiledMachine) Steps() int {
+	return m.steps
+}
+
+// Snapshot copies the machine configuration, with the tape of a machine
+// created by NewInfiniteTape.
+func (m *CompiledMachine) Snapshot() Snapshot {
+	left, right := m.tape.left, m.tape.right
+	cells := make([]Symbol, len(left)+len(right))
+	for i, id := range left {
+		cells[len(left)-1-i] = m.symbols[id]
+	}
+	for i, id := range right {
+		cells[len(left)+i] = m.symbols[id]
+	}
+	return Snapshot{
+		State:  m.State(),
+		Steps:  m.steps,
+		Pos:    m.pos,
+		MinPos: m.minPos,
+		MaxPos: m.maxPos,
+		Tape:   TapeSnapshot{Kind: infiniteTapeKind, Shift: len(left), Cells: cells},
+	}
+}
+
+// Step executes one step of the machine. The errors are the ones of
+// Machine.Step.
+func (m *CompiledMachine) Step() error {
+	if m.halted() {
+		return m.stepError(nil, newKindError(ErrHalted, "machine is halted, state %s", m.State().String()))
+	}
+	symbol := m.tape.get(m.pos)
+	op := m.program.find(m.state, symbol)
+	if !op.defined {
+		return m.noOperation(symbol)
+	}
+	m.apply(op)
+	return nil
+}
+
+// apply executes the operation.
+func (m *CompiledMachine) apply(op compiledOp) {
+	if op.write != keepSymbol {
+		m.tape.set(m.pos, op.write)
+	}
+	m.pos += op.move
+	if m.pos < m.minPos {
+		m.minPos = m.pos
+	}
+	if m.pos > m.maxPos {
+		m.maxPos = m.pos
+	}
+	m.state = op.next
+	m.steps++
+}
+
+// noOperation returns the error of a step without operation for the symbol.
+func (m *CompiledMachine) noOperation(symbol int) error {
+	state := m.State()
+	if m.state >= len(m.program.hasOps) || !m.program.hasOps[m.state] {
+		return m.stepError(m.symbols[symbol], newKindError(ErrNoStateOp, "no operation for state %v", state))
+	}
+	return m.stepError(m.symbols[symbol], newKindError(ErrNoSymbolOp, "no operation for state %v and symbol %v", state, m.symbols[symbol]))
+}
+
+func (m *CompiledMachine) stepError(symbol Symbol, err error) error {
+	return &StepError{Step: m.steps + 1, State: m.State(), Symbol: symbol, Pos: m.pos, Err: err}
+}
+
+func (m *CompiledMachine) halted() bool {
+	return m.states[m.state].Halt
+}
+
+// Run executes the program until it reaches a halt state or there is no
+// operation for the current state and symbol, like Machine.Run.
+func (m *CompiledMachine) Run() error {
+	result, err := m.RunContext(context.Background(), 0)
+	if err != nil {
+		return fmt.Errorf("Error at instruction %d: %w", result.Steps+1, err)
+	}
+	return nil
+}
+
+// compiledContextSteps is the number of steps a compiled machine executes
+// between the checks of the run context.
+const compiledContextSteps = 4096
+
+// RunContext executes the program like Machine.RunContext. The context is
+// checked before the first step and then periodically, instead of before
+// every step.
+func (m *CompiledMachine) RunContext(ctx context.Context, maxSteps int) (RunResult, error) {
+	result := RunResult{}
+	for !m.halted() {
+		if maxSteps > 0 && result.Steps >= maxSteps {
+			result.Reason = BudgetExhausted
+			return result, nil
+		}
+		if result.Steps%compiledContextSteps == 0 {
+			select {
+			case <-ctx.Done():
+				result.Reason = Cancelled
+				return result, ctx.Err()
+			default:
+			}
+		}
+
+		symbol := m.tape.get(m.pos)
+		op := m.program.find(m.state, symbol)
+		if !op.defined {
+			result.Reason = NoOperation
+			return result, m.noOperation(symbol)
+		}
+		m.apply(op)
+		result.Steps++
+	}
+	result.Reason = Halted
+	return result, nil
+}
@@ -0,0 +1,247 @@
+package turing_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+// loadTestdata reads the definitions of the testdata directory.
+func loadTestdata(t testing.TB) map[string]*turing.Definition {
+	paths, err := filepath.Glob("testdata/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defs := make(map[string]*turing.Definition)
+	for _, path := range paths {
+		def, err := turing.LoadDefinition(path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defs[filepath.Base(path)] = def
+	}
+	return defs
+}
+
+// busyBeaver returns the 4 states busy beaver, that halts after 107 steps.
+func busyBeaver() *turing.Definition {
+	a := turing.State{"a", false}
+	b := turing.State{"b", false}
+	c := turing.State{"c", false}
+	d := turing.State{"d", false}
+	halt := turing.State{"halt", true}
+	program := turing.Program{}
+	for _, op := range []turing.Op{
+		{a, nil, 1, turing.RIGHT, b},
+		{a, 1, 1, turing.LEFT, b},
+		{b, nil, 1, turing.LEFT, a},
+		{b, 1, nil, turing.LEFT, c},
+		{c, nil, 1, turing.RIGHT, halt},
+		{c, 1, 1, turing.LEFT, d},
+		{d, nil, 1, turing.RIGHT, d},
+		{d, 1, nil, turing.RIGHT, a},
+	} {
+		program.AddOp(op)
+	}
+	return &turing.Definition{Program: &program, Start: a}
+}
+
+// counter returns a binary counter that never halts.
+func counter() *turing.Definition {
+	inc := turing.State{"inc", false}
+	ret := turing.State{"ret", false}
+	program := turing.Program{}
+	for _, op := range []turing.Op{
+		{inc, 1, 0, turing.LEFT, inc},
+		{inc, 0, 1, turing.RIGHT, ret},
+		{inc, nil, 1, turing.RIGHT, ret},
+		{ret, turing.ANY, turing.KEEP, turing.RIGHT, ret},
+		{ret, nil, nil, turing.LEFT, inc},
+	} {
+		program.AddOp(op)
+	}
+	return &turing.Definition{Program: &program, Start: inc}
+}
+
+func TestCompiledMachine(t *testing.T) {
+	// assertSame runs the definition on interpreted and compiled machines,
+	// and compares the results.
+	assertSame := func(t *testing.T, name string, def *turing.Definition, maxSteps int) {
+		machine := def.NewMachine()
+		compiled := def.NewCompiledMachine()
+
+		expected, expectedErr := machine.RunContext(context.Background(), maxSteps)
+		actual, actualErr := compiled.RunContext(context.Background(), maxSteps)
+		assert.Equal(t, expected, actual, name)
+		assert.Equal(t, expectedErr, actualErr, name)
+		assert.Equal(t, machine.State, compiled.State(), name)
+		assert.Equal(t, machine.Steps(), compiled.Steps(), name)
+
+		snapshot, err := machine.Snapshot()
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, snapshot, compiled.Snapshot(), name)
+		}
+		assert.Equal(t, machine.Step(), compiled.Step(), name)
+	}
+
+	t.Run("Testdata", func(t *testing.T) {
+		t.Log("should run the testdata definitions like the interpreted machine")
+
+		defs := loadTestdata(t)
+		assert.Len(t, defs, 5)
+		for name, def := range defs {
+			assertSame(t, name, def, 1000)
+		}
+	})
+
+	t.Run("Tapes", func(t *testing.T) {
+		t.Log("should run on tapes with any position and symbols like the interpreted machine")
+
+		start, program := createSeparate01()
+		tests := []struct {
+			name   string
+			tape   []turing.Symbol
+			offset int
+			head   int
+		}{
+			{"Empty", nil, 0, 0},
+			{"Separate", []turing.Symbol{0, 1, 0, 1, 0, 1, 1, 1, 0, 1}, 0, 2},
+			{"Negative", []turing.Symbol{1, 1, 0, 0, 1, 0}, -50, -48},
+			{"UnknownSymbol", []turing.Symbol{0, 1, "x", 1, 0}, -2, -2},
+			{"AnyUnknownSymbol", []turing.Symbol{1, 0, "x", 1, nil, 0}, 3, 3},
+		}
+		for _, tt := range tests {
+			def := turing.Definition{Program: program, Start: start, Tape: tt.tape, TapeOffset: tt.offset, HeadPos: tt.head}
+			assertSame(t, tt.name, &def, 0)
+		}
+	})
+
+	t.Run("Examples", func(t *testing.T) {
+		t.Log("should run the example programs like the interpreted machine")
+
+		zeroAllStart, zeroAllProgram := createZeroAll()
+		mirrorStart, mirrorProgram := createMirror()
+		copyStart, copyProgram := createCopy()
+		textMirror, err := turing.ParseText(strings.NewReader(mirrorText))
+		if !assert.NoError(t, err) {
+			return
+		}
+		tests := []struct {
+			name string
+			def  *turing.Definition
+		}{
+			{"ZeroAll", &turing.Definition{Program: zeroAllProgram, Start: zeroAllStart, Tape: []turing.Symbol{1, 1, 1, 0, 1, 1, 0, 1, 0, 1}}},
+			{"Mirror", &turing.Definition{Program: mirrorProgram, Start: mirrorStart, Tape: []turing.Symbol{1, 0, 1}}},
+			{"Copy", &turing.Definition{Program: copyProgram, Start: copyStart, Tape: []turing.Symbol{1, 1, 1, 0, 1, 1, 0, 1, 0, 1}}},
+			{"TextMirror", textMirror},
+		}
+		for _, tt := range tests {
+			assertSame(t, tt.name, tt.def, 0)
+		}
+	})
+
+	t.Run("Programs", func(t *testing.T) {
+		t.Log("should run programs like the interpreted machine")
+
+		beaver := busyBeaver()
+		assertSame(t, "BusyBeaver", beaver, 0)
+		result, err := beaver.NewCompiledMachine().RunContext(context.Background(), 0)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.RunResult{Steps: 107, Reason: turing.Halted}, result)
+		}
+
+		assertSame(t, "Counter", counter(), 12345)
+
+		unknown := counter()
+		unknown.Start = turing.State{"unknown", false}
+		assertSame(t, "UnknownState", unknown, 0)
+
+		halted := counter()
+		halted.Start = turing.State{"stop", true}
+		assertSame(t, "Halted", halted, 0)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Log("should return the typed errors of the interpreted machine")
+
+		def := counter()
+		def.Tape = []turing.Symbol{1, 1, 1, "x"}
+		def.HeadPos = 2
+		compiled := def.NewCompiledMachine()
+		err := compiled.Run()
+		assert.EqualError(t, err, "Error at instruction 10: no operation for state inc and symbol x")
+		assert.True(t, errors.Is(err, turing.ErrNoSymbolOp))
+		var stepErr *turing.StepError
+		if assert.True(t, errors.As(err, &stepErr)) {
+			assert.Equal(t, 10, stepErr.Step)
+			assert.Equal(t, "x", stepErr.Symbol)
+			assert.Equal(t, 3, stepErr.Pos)
+		}
+		assert.EqualError(t, def.NewMachine().Run(), err.Error())
+	})
+
+	t.Run("Load", func(t *testing.T) {
+		t.Log("should resume the run of an interpreted machine, and back")
+
+		def := counter()
+		machine := def.NewMachine()
+		machine.RunContext(context.Background(), 500)
+
+		snapshot, _ := machine.Snapshot()
+		compiled, err := turing.Compile(def.Program).Load(snapshot)
+		if !assert.NoError(t, err) {
+			return
+		}
+		compiled.RunContext(context.Background(), 500)
+		machine.RunContext(context.Background(), 500)
+		assert.Equal(t, 1000, compiled.Steps())
+
+		resumed := turing.Machine{Program: def.Program}
+		if assert.NoError(t, resumed.Restore(compiled.Snapshot())) {
+			expected, _ := machine.Snapshot()
+			actual, _ := resumed.Snapshot()
+			assert.Equal(t, expected, actual)
+		}
+
+		_, err = turing.Compile(def.Program).Load(turing.Snapshot{Tape: turing.TapeSnapshot{Kind: "paper"}})
+		assert.EqualError(t, err, `unknown tape kind "paper"`)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Log("should not run when the context is cancelled")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		result, err := counter().NewCompiledMachine().RunContext(ctx, 0)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, turing.RunResult{Reason: turing.Cancelled}, result)
+	})
+}
+
+// BenchmarkCompiledMachine compares the interpreted and compiled machines
+// running the binary counter.
+func BenchmarkCompiledMachine(b *testing.B) {
+	const steps = 100000
+	def := counter()
+	runners := []struct {
+		name string
+		run  func()
+	}{
+		{"Interpreted", func() { def.NewMachine().RunContext(context.Background(), steps) }},
+		{"Compiled", func() { def.NewCompiledMachine().RunContext(context.Background(), steps) }},
+	}
+	for _, r := range runners {
+		b.Run(fmt.Sprintf("%s/%d", r.name, steps), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				r.run()
+			}
+		})
+	}
+}
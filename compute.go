@@ -0,0 +1,286 @@
+package turing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Encoding converts the inputs of a computation to tape symbols, and the
+// output symbols back to values.
+type Encoding interface {
+	// Encode returns the symbols of the input.
+	Encode(input interface{}) ([]Symbol, error)
+	// Decode returns the value of the output symbols.
+	Decode(output []Symbol) (interface{}, error)
+}
+
+// Standard encodings.
+var (
+	// Unary encodes natural numbers, as ints, with one symbol 1 per unit.
+	Unary Encoding = unaryEncoding{}
+	// Binary encodes natural numbers, as ints, with the symbols 0 and 1 and
+	// the most significant bit first.
+	Binary Encoding = binaryEncoding{}
+	// SymbolString encodes strings with one symbol per character: the int
+	// value of digits, nil for _ and the character as a string otherwise. It
+	// only decodes int symbols from 0 to 9.
+	SymbolString Encoding = symbolStringEncoding{}
+	// Bytes encodes byte slices with the int value of each byte.
+	Bytes Encoding = bytesEncoding{}
+)
+
+// natural returns the input as a natural number.
+func natural(input interface{}) (int, error) {
+	n, ok := input.(int)
+	if !ok || n < 0 {
+		return 0, fmt.Errorf("input %v is not a natural number", input)
+	}
+	return n, nil
+}
+
+type unaryEncoding struct{}
+
+func (unaryEncoding) Encode(input interface{}) ([]Symbol, error) {
+	n, err := natural(input)
+	if err != nil {
+		return nil, err
+	}
+	symbols := make([]Symbol, n)
+	for i := range symbols {
+		symbols[i] = 1
+	}
+	return symbols, nil
+}
+
+func (unaryEncoding) Decode(output []Symbol) (interface{}, error) {
+	for _, s := range output {
+		if s != 1 {
+			return nil, fmt.Errorf("output %v is not an unary number", output)
+		}
+	}
+	return len(output), nil
+}
+
+type binaryEncoding struct{}
+
+func (binaryEncoding) Encode(input interface{}) ([]Symbol, error) {
+	n, err := natural(input)
+	if err != nil {
+		return nil, err
+	}
+	var symbols []Symbol
+	for _, digit := range strconv.FormatInt(int64(n), 2) {
+		symbols = append(symbols, int(digit-'0'))
+	}
+	return symbols, nil
+}
+
+func (binaryEncoding) Decode(output []Symbol) (interface{}, error) {
+	if len(output) == 0 {
+		return nil, fmt.Errorf("output %v is not a binary number", output)
+	}
+	n := 0
+	for _, s := range output {
+		if s != 0 && s != 1 {
+			return nil, fmt.Errorf("output %v is not a binary number", output)
+		}
+		n = 2*n + s.(int)
+	}
+	return n, nil
+}
+
+type symbolStringEncoding struct{}
+
+func (symbolStringEncoding) Encode(input interface{}) ([]Symbol, error) {
+	text, ok := input.(string)
+	if !ok {
+		return nil, fmt.Errorf("input %v is not a string", input)
+	}
+	var symbols []Symbol
+	for _, r := range text {
+		switch {
+		case r == '_':
+			symbols = append(symbols, nil)
+		case r >= '0' && r <= '9':
+			symbols = append(symbols, int(r-'0'))
+		default:
+			symbols = append(symbols, string(r))
+		}
+	}
+	return symbols, nil
+}
+
+func (symbolStringEncoding) Decode(output []Symbol) (interface{}, error) {
+	builder := strings.Builder{}
+	for _, s := range output {
+		switch v := s.(type) {
+		case nil:
+			builder.WriteByte('_')
+		case int:
+			if v < 0 || v > 9 {
+				return nil, fmt.Errorf("output %v is not a symbol string", output)
+			}
+			builder.WriteString(strconv.Itoa(v))
+		case string:
+			builder.WriteString(v)
+		default:
+			return nil, fmt.Errorf("output %v is not a symbol string", output)
+		}
+	}
+	return builder.String(), nil
+}
+
+type bytesEncoding struct{}
+
+func (bytesEncoding) Encode(input interface{}) ([]Symbol, error) {
+	data, ok := input.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("input %v is not a byte slice", input)
+	}
+	symbols := make([]Symbol, len(data))
+	for i, b := range data {
+		symbols[i] = int(b)
+	}
+	return symbols, nil
+}
+
+func (bytesEncoding) Decode(output []Symbol) (interface{}, error) {
+	data := make([]byte, len(output))
+	for i, s := range output {
+		n, ok := s.(int)
+		if !ok || n < 0 || n > 255 {
+			return nil, fmt.Errorf("output %v is not a byte slice", output)
+		}
+		data[i] = byte(n)
+	}
+	return data, nil
+}
+
+// OutputConvention returns the output symbols of a halted machine.
+type OutputConvention func(m *Machine) ([]Symbol, error)
+
+// HeadBlock is the OutputConvention of the maximal block of non-blank cells
+// under the head, or of the first block on the right of the head when it is
+// on a blank cell.
+func HeadBlock(m *Machine) ([]Symbol, error) {
+	snapshot, err := m.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	cells := snapshot.Tape.Cells
+	start := m.Head.Pos() + snapshot.Tape.Shift
+	if start < 0 {
+		start = 0
+	}
+	for start < len(cells) && cells[start] == nil {
+		start++
+	}
+	if start >= len(cells) {
+		return []Symbol{}, nil
+	}
+	end := start
+	for start > 0 && cells[start-1] != nil {
+		start--
+	}
+	for end < len(cells) && cells[end] != nil {
+		end++
+	}
+	return cells[start:end], nil
+}
+
+// NonBlankTape is the OutputConvention of the cells from the first to the
+// last non-blank cell of the tape.
+func NonBlankTape(m *Machine) ([]Symbol, error) {
+	snapshot, err := m.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	cells := snapshot.Tape.Cells
+	for len(cells) > 0 && cells[0] == nil {
+		cells = cells[1:]
+	}
+	for len(cells) > 0 && cells[len(cells)-1] == nil {
+		cells = cells[:len(cells)-1]
+	}
+	return cells, nil
+}
+
+// DefaultComputeSteps is the step budget of computations when
+// Computation.MaxSteps is not set.
+const DefaultComputeSteps = 1000000
+
+// Computation is a function computed by a program. The input is written on a
+// new infinite tape from position 0, where the head starts, and the output is
+// read from the tape of the halted machine.
+type Computation struct {
+	// Program is the machine program.
+	Program *Program
+	// Start is the initial state.
+	Start State
+	// Input is the input encoding.
+	Input Encoding
+	// Output is the output encoding. It is Input when not set.
+	Output Encoding
+	// Convention returns the output symbols. It is HeadBlock when not set.
+	Convention OutputConvention
+	// MaxSteps is the step budget of each run. It is DefaultComputeSteps
+	// when smaller than 1.
+	MaxSteps int
+}
+
+// NewMachine creates a machine with the encoded input on its tape.
+func (c *Computation) NewMachine(input interface{}) (*Machine, error) {
+	tape, err := c.Input.Encode(input)
+	if err != nil {
+		return nil, err
+	}
+	def := Definition{Program: c.Program, Start: c.Start, Tape: tape}
+	return def.NewMachine(), nil
+}
+
+// Decode returns the output of the halted machine.
+func (c *Computation) Decode(m *Machine) (interface{}, error) {
+	convention := c.Convention
+	if convention == nil {
+		convention = HeadBlock
+	}
+	output, err := convention(m)
+	if err != nil {
+		return nil, err
+	}
+	encoding := c.Output
+	if encoding == nil {
+		encoding = c.Input
+	}
+	return encoding.Decode(output)
+}
+
+// Compute runs the machine on the input and returns its output. The error is
+// the Step error when the machine stops without halting, and matches
+// ErrNotHalted when it does not halt within the step budget.
+func (c *Computation) Compute(input interface{}) (interface{}, error) {
+	return c.ComputeContext(context.Background(), input)
+}
+
+// ComputeContext runs the machine like Compute, but also stops when ctx is
+// done, returning ctx.Err().
+func (c *Computation) ComputeContext(ctx context.Context, input interface{}) (interface{}, error) {
+	m, err := c.NewMachine(input)
+	if err != nil {
+		return nil, err
+	}
+	maxSteps := c.MaxSteps
+	if maxSteps < 1 {
+		maxSteps = DefaultComputeSteps
+	}
+	result, err := m.RunContext(ctx, maxSteps)
+	if err != nil {
+		return nil, err
+	}
+	if result.Reason != Halted {
+		return nil, newKindError(ErrNotHalted, "machine did not halt after %d steps", result.Steps)
+	}
+	return c.Decode(m)
+}
@@ -0,0 +1,211 @@
+package turing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/massahud/turing/machines"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodings(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Log("should decode the encoded inputs")
+
+		tests := []struct {
+			name     string
+			encoding turing.Encoding
+			input    interface{}
+			symbols  []turing.Symbol
+		}{
+			{"Unary", turing.Unary, 3, []turing.Symbol{1, 1, 1}},
+			{"UnaryZero", turing.Unary, 0, []turing.Symbol{}},
+			{"Binary", turing.Binary, 6, []turing.Symbol{1, 1, 0}},
+			{"BinaryZero", turing.Binary, 0, []turing.Symbol{0}},
+			{"SymbolString", turing.SymbolString, "a1_(", []turing.Symbol{"a", 1, nil, "("}},
+			{"Bytes", turing.Bytes, []byte{0, 7, 255}, []turing.Symbol{0, 7, 255}},
+		}
+		for _, tt := range tests {
+			symbols, err := tt.encoding.Encode(tt.input)
+			if assert.NoError(t, err, tt.name) {
+				assert.Equal(t, len(tt.symbols), len(symbols), tt.name)
+				for i := range tt.symbols {
+					assert.Equal(t, tt.symbols[i], symbols[i], tt.name)
+				}
+			}
+			output, err := tt.encoding.Decode(tt.symbols)
+			if assert.NoError(t, err, tt.name) {
+				assert.Equal(t, tt.input, output, tt.name)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Log("should not encode or decode values of other types")
+
+		_, err := turing.Unary.Encode(-1)
+		assert.EqualError(t, err, "input -1 is not a natural number")
+		_, err = turing.Binary.Encode("3")
+		assert.EqualError(t, err, "input 3 is not a natural number")
+		_, err = turing.SymbolString.Encode(3)
+		assert.EqualError(t, err, "input 3 is not a string")
+		_, err = turing.Bytes.Encode("abc")
+		assert.EqualError(t, err, "input abc is not a byte slice")
+
+		_, err = turing.Unary.Decode([]turing.Symbol{1, 0})
+		assert.EqualError(t, err, "output [1 0] is not an unary number")
+		_, err = turing.Binary.Decode([]turing.Symbol{})
+		assert.EqualError(t, err, "output [] is not a binary number")
+		_, err = turing.Binary.Decode([]turing.Symbol{1, 2})
+		assert.EqualError(t, err, "output [1 2] is not a binary number")
+		_, err = turing.SymbolString.Decode([]turing.Symbol{1.5})
+		assert.EqualError(t, err, "output [1.5] is not a symbol string")
+		_, err = turing.SymbolString.Decode([]turing.Symbol{12})
+		assert.EqualError(t, err, "output [12] is not a symbol string")
+		_, err = turing.SymbolString.Decode([]turing.Symbol{1, -2})
+		assert.EqualError(t, err, "output [1 -2] is not a symbol string")
+		_, err = turing.Bytes.Decode([]turing.Symbol{1, 256})
+		assert.EqualError(t, err, "output [1 256] is not a byte slice")
+	})
+}
+
+func TestOutputConventions(t *testing.T) {
+	// machine returns a machine with the tape and the head on pos.
+	machine := func(tape []turing.Symbol, pos int) *turing.Machine {
+		def := turing.Definition{Program: &turing.Program{}, Tape: tape, TapeOffset: -3, HeadPos: pos}
+		return def.NewMachine()
+	}
+	tape := []turing.Symbol{nil, "a", "b", nil, "c", "d", "e", nil}
+
+	t.Run("HeadBlock", func(t *testing.T) {
+		t.Log("should return the block under the head or on its right")
+
+		tests := []struct {
+			pos    int
+			output []turing.Symbol
+		}{
+			{-3, []turing.Symbol{"a", "b"}},
+			{-1, []turing.Symbol{"a", "b"}},
+			{0, []turing.Symbol{"c", "d", "e"}},
+			{2, []turing.Symbol{"c", "d", "e"}},
+			{-10, []turing.Symbol{"a", "b"}},
+			{4, []turing.Symbol{}},
+			{10, []turing.Symbol{}},
+		}
+		for _, tt := range tests {
+			output, err := turing.HeadBlock(machine(tape, tt.pos))
+			if assert.NoError(t, err, tt.pos) {
+				assert.Equal(t, tt.output, append([]turing.Symbol{}, output...), tt.pos)
+			}
+		}
+	})
+
+	t.Run("NonBlankTape", func(t *testing.T) {
+		t.Log("should return the cells from the first to the last non-blank cell")
+
+		output, err := turing.NonBlankTape(machine(tape, 0))
+		if assert.NoError(t, err) {
+			assert.Equal(t, []turing.Symbol{"a", "b", nil, "c", "d", "e"}, output)
+		}
+		output, err = turing.NonBlankTape(machine(nil, 0))
+		if assert.NoError(t, err) {
+			assert.Empty(t, output)
+		}
+	})
+}
+
+func TestComputation(t *testing.T) {
+	t.Run("Compute", func(t *testing.T) {
+		t.Log("should compute the output of the encoded input")
+
+		increment := machines.BinaryIncrement()
+		c := turing.Computation{Program: increment.Program, Start: increment.Start, Input: turing.Binary}
+		for n := 0; n < 20; n++ {
+			output, err := c.Compute(n)
+			if assert.NoError(t, err, n) {
+				assert.Equal(t, n+1, output, n)
+			}
+		}
+
+		toUnary := machines.BinaryToUnary()
+		c = turing.Computation{Program: toUnary.Program, Start: toUnary.Start, Input: turing.Binary, Output: turing.Unary}
+		output, err := c.Compute(13)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 13, output)
+		}
+
+		separate := machines.SeparateZeroOne()
+		c = turing.Computation{Program: separate.Program, Start: separate.Start, Input: turing.Bytes, Convention: turing.NonBlankTape}
+		output, err = c.Compute([]byte{1, 0, 1, 1, 0})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte{0, 0, 1, 1, 1}, output)
+		}
+	})
+
+	t.Run("Machine", func(t *testing.T) {
+		t.Log("should create the machine of the input, and decode its output after the run")
+
+		copier := machines.Copy()
+		c := turing.Computation{Program: copier.Program, Start: copier.Start, Input: turing.SymbolString, Convention: turing.NonBlankTape}
+		m, err := c.NewMachine("011")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 0, m.Head.Pos())
+		assert.NoError(t, m.Run())
+		output, err := c.Decode(m)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "011_011", output)
+		}
+
+		_, err = c.NewMachine([]byte("011"))
+		assert.EqualError(t, err, "input [48 49 49] is not a string")
+	})
+
+	t.Run("BoundedTape", func(t *testing.T) {
+		t.Log("should decode the output of machines on bounded tapes")
+
+		separate := machines.SeparateZeroOne()
+		tape := turing.NewBoundedTape(-2, 7, "<", ">")
+		tape.Set(0, 1, 0, 1, 1, 0)
+		head := turing.Head{}
+		head.Attach(tape, 0)
+		m := turing.Machine{Head: &head, Program: separate.Program, State: separate.Start}
+		if !assert.NoError(t, m.Run()) {
+			return
+		}
+
+		c := turing.Computation{Program: separate.Program, Start: separate.Start, Input: turing.Bytes, Convention: turing.NonBlankTape}
+		output, err := c.Decode(&m)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte{0, 0, 1, 1, 1}, output)
+		}
+		c.Convention = turing.HeadBlock
+		output, err = c.Decode(&m)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte{0, 0, 1, 1, 1}, output)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Log("should return the run errors")
+
+		def := counter()
+		c := turing.Computation{Program: def.Program, Start: def.Start, Input: turing.Binary, MaxSteps: 50}
+		_, err := c.Compute(5)
+		assert.EqualError(t, err, "machine did not halt after 50 steps")
+		assert.True(t, errors.Is(err, turing.ErrNotHalted))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = c.ComputeContext(ctx, 5)
+		assert.Equal(t, context.Canceled, err)
+
+		c.Input = turing.SymbolString
+		_, err = c.Compute("10x")
+		assert.EqualError(t, err, "no operation for state inc and symbol x")
+		assert.True(t, errors.Is(err, turing.ErrNoSymbolOp))
+	})
+}
@@ -0,0 +1,539 @@
+// Package debugger is an interactive step debugger for turing machines.
+//
+// The debugger reads commands, one per line, and writes the machine
+// configuration every time it stops. The commands are:
+//
+//	step [n]                  executes one or n steps
+//	continue                  runs until a breakpoint, watchpoint or halt
+//	until STATE               runs until the machine is on STATE
+//	back STATE                goes back to the last step on STATE
+//	break state STATE         stops before executing on STATE
+//	break symbol SYMBOL       stops before executing on SYMBOL
+//	break at STATE SYMBOL     stops before executing on STATE and SYMBOL
+//	break pos POS             stops before executing on position POS
+//	watch POS                 stops after the cell on POS changes
+//	delete [ID]               deletes one, or all, breakpoints and watchpoints
+//	list                      lists the breakpoints and watchpoints
+//	tape [RADIUS]             prints the tape around the head
+//	info                      prints the machine configuration
+//	help                      prints the commands
+//	quit                      ends the debugger
+//
+// Commands can be abbreviated to their first letter, and an empty line
+// repeats the previous command. Symbols are written as in the tape argument
+// of the turing command: _ is the blank symbol, and numbers are integers
+// unless the program only uses them as strings.
+package debugger
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/massahud/turing"
+)
+
+// DefaultMaxSteps is the maximum number of steps continue and until execute
+// when Debugger.MaxSteps is not set.
+const DefaultMaxSteps = 1000000
+
+// Debugger runs a machine step by step.
+type Debugger struct {
+	// Machine is the debugged machine.
+	Machine *turing.Machine
+	// MaxSteps is the maximum number of steps continue and until execute
+	// before stopping, DefaultMaxSteps when it is smaller than 1.
+	MaxSteps int
+
+	points   []point
+	nextID   int
+	alphabet map[turing.Symbol]bool
+	timeline *turing.Timeline
+	stop     Stop
+}
+
+// StopReason tells why the debugger stopped the machine.
+type StopReason int
+
+const (
+	// Paused means no command executed steps yet, or the last one executed
+	// all the steps requested.
+	Paused StopReason = iota
+	// Breakpoint means the machine stopped on a breakpoint.
+	Breakpoint
+	// Watchpoint means the machine stopped after a watched cell changed.
+	Watchpoint
+	// StateReached means until or back stopped on the requested state.
+	StateReached
+	// MachineStopped means the run of the machine stopped, for the reason in
+	// Stop.Run.
+	MachineStopped
+)
+
+// Stop is why the last command that executes steps stopped.
+type Stop struct {
+	// Reason is why the debugger stopped the machine.
+	Reason StopReason
+	// Run is why the run stopped when Reason is MachineStopped: the machine
+	// halted, failed a step, was on a cycle or executed the maximum number
+	// of steps of continue or until.
+	Run turing.StopReason
+	// Err is the step error when the step failed, or the *turing.LoopError
+	// when the machine is on a cycle.
+	Err error
+}
+
+// pointKind is the kind of a breakpoint or watchpoint.
+type pointKind int
+
+const (
+	breakState pointKind = iota
+	breakSymbol
+	breakStateSymbol
+	breakPos
+	watchCell
+)
+
+// point is a breakpoint or a watchpoint.
+type point struct {
+	id     int
+	kind   pointKind
+	state  string
+	symbol turing.Symbol
+	pos    int
+}
+
+func (p point) String() string {
+	switch p.kind {
+	case breakState:
+		return fmt.Sprintf("breakpoint %d: state %s", p.id, p.state)
+	case breakSymbol:
+		return fmt.Sprintf("breakpoint %d: symbol %v", p.id, p.symbol)
+	case breakStateSymbol:
+		return fmt.Sprintf("breakpoint %d: state %s and symbol %v", p.id, p.state, p.symbol)
+	case breakPos:
+		return fmt.Sprintf("breakpoint %d: position %d", p.id, p.pos)
+	}
+	return fmt.Sprintf("watchpoint %d: position %d", p.id, p.pos)
+}
+
+// New creates a debugger for the machine. It records the steps of the
+// machine from its current step on, with a Timeline added to the machine
+// observer, to go back to them.
+func New(m *turing.Machine) *Debugger {
+	alphabet := make(map[turing.Symbol]bool)
+	if m.Program != nil {
+		for _, op := range m.Program.ListOps() {
+			alphabet[op.Symbol] = true
+			alphabet[op.WriteSymbol] = true
+		}
+	}
+	timeline := turing.NewTimeline(m)
+	if m.Observer == nil {
+		m.Observer = timeline
+	} else {
+		m.Observer = turing.Observers{m.Observer, timeline}
+	}
+	return &Debugger{Machine: m, nextID: 1, alphabet: alphabet, timeline: timeline}
+}
+
+// LastStop returns why the last command that executes steps stopped.
+func (d *Debugger) LastStop() Stop {
+	return d.stop
+}
+
+// Run reads commands from r and writes their output into w, until quit or
+// the end of r. It returns an error only when reading or writing fails.
+func (d *Debugger) Run(r io.Reader, w io.Writer) error {
+	out := bufio.NewWriter(w)
+	scanner := bufio.NewScanner(r)
+	last := ""
+	for {
+		fmt.Fprint(out, "(turing) ")
+		if err := out.Flush(); err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			if err := out.Flush(); err != nil {
+				return err
+			}
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			line = last
+		}
+		last = line
+		if line == "" {
+			continue
+		}
+		if quit := d.Execute(line, out); quit {
+			return out.Flush()
+		}
+	}
+}
+
+// Execute runs one command, writing its output into w. It returns true for
+// the quit command.
+func (d *Debugger) Execute(line string, w io.Writer) bool {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return false
+	}
+
+	var err error
+	switch args[0] {
+	case "step", "s":
+		err = d.stepCommand(args[1:], w)
+	case "continue", "c":
+		err = d.continueCommand(args[1:], w)
+	case "until", "u":
+		err = d.untilCommand(args[1:], w)
+	case "back":
+		err = d.backCommand(args[1:], w)
+	case "break", "b":
+		err = d.breakCommand(args[1:], w)
+	case "watch", "w":
+		err = d.watchCommand(args[1:], w)
+	case "delete", "d":
+		err = d.deleteCommand(args[1:], w)
+	case "list", "l":
+		err = d.listCommand(args[1:], w)
+	case "tape", "t":
+		err = d.tapeCommand(args[1:], w)
+	case "info", "i":
+		err = d.noArgs(args[1:])
+		if err == nil {
+			d.printInfo(w)
+		}
+	case "help", "h":
+		err = d.noArgs(args[1:])
+		if err == nil {
+			fmt.Fprint(w, help)
+		}
+	case "quit", "q":
+		return true
+	default:
+		err = fmt.Errorf("unknown command %q, try help", args[0])
+	}
+	if err != nil {
+		fmt.Fprintf(w, "error: %s\n", err.Error())
+	}
+	return false
+}
+
+const help = `step [n]                  executes one or n steps
+continue                  runs until a breakpoint, watchpoint or halt
+until STATE               runs until the machine is on STATE
+back STATE                goes back to the last step on STATE
+break state STATE         stops before executing on STATE
+break symbol SYMBOL       stops before executing on SYMBOL
+break at STATE SYMBOL     stops before executing on STATE and SYMBOL
+break pos POS             stops before executing on position POS
+watch POS                 stops after the cell on POS changes
+delete [ID]               deletes one, or all, breakpoints and watchpoints
+list                      lists the breakpoints and watchpoints
+tape [RADIUS]             prints the tape around the head
+info                      prints the machine configuration
+help                      prints the commands
+quit                      ends the debugger
+`
+
+func (d *Debugger) noArgs(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments %v", args)
+	}
+	return nil
+}
+
+func (d *Debugger) stepCommand(args []string, w io.Writer) error {
+	n := 1
+	if len(args) > 1 {
+		return fmt.Errorf("usage: step [n]")
+	}
+	if len(args) == 1 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			return fmt.Errorf("invalid number of steps %q", args[0])
+		}
+	}
+	d.run(n, false, nil, w)
+	return nil
+}
+
+func (d *Debugger) continueCommand(args []string, w io.Writer) error {
+	if err := d.noArgs(args); err != nil {
+		return err
+	}
+	d.run(d.maxSteps(), true, nil, w)
+	return nil
+}
+
+func (d *Debugger) untilCommand(args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: until STATE")
+	}
+	name := args[0]
+	d.run(d.maxSteps(), true, func(m *turing.Machine) bool {
+		return m.State.Name == name
+	}, w)
+	return nil
+}
+
+func (d *Debugger) backCommand(args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: back STATE")
+	}
+	name := args[0]
+	m := d.Machine
+	from := m.Steps()
+	for step := from - 1; step >= d.timeline.First(); step-- {
+		if err := d.timeline.Goto(step); err != nil {
+			return err
+		}
+		if m.State.Name == name {
+			d.stop = Stop{Reason: StateReached}
+			d.printInfo(w)
+			return nil
+		}
+	}
+	if err := d.timeline.Goto(from); err != nil {
+		return err
+	}
+	return fmt.Errorf("no recorded step on state %s", name)
+}
+
+func (d *Debugger) maxSteps() int {
+	if d.MaxSteps < 1 {
+		return DefaultMaxSteps
+	}
+	return d.MaxSteps
+}
+
+// run executes up to n steps, stopping on the breakpoints and watchpoints,
+// or after a step when stop returns true. When budget is set, executing the
+// n steps stops the run like the step budget of RunContext.
+func (d *Debugger) run(n int, budget bool, stop func(m *turing.Machine) bool, w io.Writer) {
+	m := d.Machine
+	var events []turing.StepEvent
+	recorder := turing.ObserverFunc(func(e turing.StepEvent) {
+		events = append(events, e)
+	})
+	observer := m.Observer
+	if observer == nil {
+		m.Observer = recorder
+	} else {
+		m.Observer = turing.Observers{observer, recorder}
+	}
+	defer func() {
+		m.Observer = observer
+	}()
+
+	d.stop = Stop{Reason: Paused}
+	for i := 0; i < n; i++ {
+		if m.State.Halt {
+			fmt.Fprintf(w, "machine is halted\n")
+			d.stop = Stop{Reason: MachineStopped, Run: turing.Halted}
+			break
+		}
+		if i > 0 {
+			if p, ok := d.breakpoint(); ok {
+				fmt.Fprintf(w, "%v\n", p)
+				d.stop = Stop{Reason: Breakpoint}
+				break
+			}
+		}
+
+		events = events[:0]
+		result, err := m.RunContext(context.Background(), 1)
+		if err != nil {
+			fmt.Fprintf(w, "error: %s\n", err.Error())
+			d.stop = Stop{Reason: MachineStopped, Run: result.Reason, Err: err}
+			break
+		}
+		if result.Reason == turing.Looping {
+			fmt.Fprintf(w, "machine is looping, %v\n", result.Cycle)
+			d.stop = Stop{Reason: MachineStopped, Run: turing.Looping, Err: &turing.LoopError{Cycle: result.Cycle}}
+			break
+		}
+		if p, e, ok := d.watchpoint(events); ok {
+			fmt.Fprintf(w, "%v: %v -> %v\n", p, e.Read, e.Written)
+			d.stop = Stop{Reason: Watchpoint}
+			break
+		}
+		if stop != nil && stop(m) {
+			d.stop = Stop{Reason: StateReached}
+			if m.State.Halt {
+				d.stop = Stop{Reason: MachineStopped, Run: turing.Halted}
+			}
+			break
+		}
+		if m.State.Halt {
+			d.stop = Stop{Reason: MachineStopped, Run: turing.Halted}
+		} else if i == n-1 && budget {
+			fmt.Fprintf(w, "stopped after %d steps\n", n)
+			d.stop = Stop{Reason: MachineStopped, Run: turing.BudgetExhausted}
+		}
+	}
+	d.printInfo(w)
+}
+
+// breakpoint returns the first breakpoint matching the machine
+// configuration.
+func (d *Debugger) breakpoint() (point, bool) {
+	m := d.Machine
+	symbol, _ := m.Head.Read()
+	for _, p := range d.points {
+		switch {
+		case p.kind == breakState && p.state == m.State.Name,
+			p.kind == breakSymbol && p.symbol == symbol,
+			p.kind == breakStateSymbol && p.state == m.State.Name && p.symbol == symbol,
+			p.kind == breakPos && p.pos == m.Head.Pos():
+			return p, true
+		}
+	}
+	return point{}, false
+}
+
+// watchpoint returns the first watchpoint on a cell changed by the events.
+func (d *Debugger) watchpoint(events []turing.StepEvent) (point, turing.StepEvent, bool) {
+	for _, e := range events {
+		if e.Read == e.Written {
+			continue
+		}
+		for _, p := range d.points {
+			if p.kind == watchCell && p.pos == e.From {
+				return p, e, true
+			}
+		}
+	}
+	return point{}, turing.StepEvent{}, false
+}
+
+func (d *Debugger) breakCommand(args []string, w io.Writer) error {
+	usage := fmt.Errorf("usage: break state STATE | symbol SYMBOL | at STATE SYMBOL | pos POS")
+	if len(args) < 2 {
+		return usage
+	}
+
+	p := point{}
+	switch {
+	case args[0] == "state" && len(args) == 2:
+		p.kind = breakState
+		p.state = args[1]
+	case args[0] == "symbol" && len(args) == 2:
+		p.kind = breakSymbol
+		p.symbol = d.parseSymbol(args[1])
+	case args[0] == "at" && len(args) == 3:
+		p.kind = breakStateSymbol
+		p.state = args[1]
+		p.symbol = d.parseSymbol(args[2])
+	case args[0] == "pos" && len(args) == 2:
+		pos, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid position %q", args[1])
+		}
+		p.kind = breakPos
+		p.pos = pos
+	default:
+		return usage
+	}
+
+	d.addPoint(p, w)
+	return nil
+}
+
+func (d *Debugger) watchCommand(args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: watch POS")
+	}
+	pos, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid position %q", args[0])
+	}
+	d.addPoint(point{kind: watchCell, pos: pos}, w)
+	return nil
+}
+
+func (d *Debugger) addPoint(p point, w io.Writer) {
+	p.id = d.nextID
+	d.nextID++
+	d.points = append(d.points, p)
+	fmt.Fprintf(w, "%v\n", p)
+}
+
+func (d *Debugger) deleteCommand(args []string, w io.Writer) error {
+	switch len(args) {
+	case 0:
+		d.points = nil
+		fmt.Fprintln(w, "deleted all breakpoints and watchpoints")
+		return nil
+	case 1:
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid id %q", args[0])
+		}
+		for i, p := range d.points {
+			if p.id == id {
+				d.points = append(d.points[:i], d.points[i+1:]...)
+				fmt.Fprintf(w, "deleted %v\n", p)
+				return nil
+			}
+		}
+		return fmt.Errorf("no breakpoint or watchpoint %d", id)
+	}
+	return fmt.Errorf("usage: delete [ID]")
+}
+
+func (d *Debugger) listCommand(args []string, w io.Writer) error {
+	if err := d.noArgs(args); err != nil {
+		return err
+	}
+	if len(d.points) == 0 {
+		fmt.Fprintln(w, "no breakpoints or watchpoints")
+	}
+	for _, p := range d.points {
+		fmt.Fprintf(w, "%v\n", p)
+	}
+	return nil
+}
+
+func (d *Debugger) tapeCommand(args []string, w io.Writer) error {
+	radius := 5
+	if len(args) > 1 {
+		return fmt.Errorf("usage: tape [RADIUS]")
+	}
+	if len(args) == 1 {
+		var err error
+		if radius, err = strconv.Atoi(args[0]); err != nil || radius < 0 {
+			return fmt.Errorf("invalid radius %q", args[0])
+		}
+	}
+	pos := d.Machine.Head.Pos()
+	fmt.Fprint(w, d.Machine.Head.PrintTape(pos-radius, pos+radius))
+	return nil
+}
+
+// printInfo writes the machine configuration.
+func (d *Debugger) printInfo(w io.Writer) {
+	m := d.Machine
+	symbol, _ := m.Head.Read()
+	fmt.Fprintf(w, "step %d: state %v, position %d, symbol %v\n", m.Steps(), m.State, m.Head.Pos(), symbol)
+}
+
+// parseSymbol converts the text to the symbol the program uses: nil for _,
+// the string itself or its integer value.
+func (d *Debugger) parseSymbol(text string) turing.Symbol {
+	if text == "_" {
+		return nil
+	}
+	if n, err := strconv.Atoi(text); err == nil && (d.alphabet[n] || !d.alphabet[text]) {
+		return n
+	}
+	return text
+}
@@ -0,0 +1,234 @@
+package debugger_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/massahud/turing/debugger"
+	"github.com/stretchr/testify/assert"
+)
+
+// createDebugger creates a debugger for the separate01 machine, with tape
+// 0 1 0 1 0 1 1 1 0 1.
+func createDebugger(t *testing.T) *debugger.Debugger {
+	file, err := os.Open("../testdata/separate01.tm")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer file.Close()
+
+	def, err := turing.ParseText(file)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return debugger.New(def.NewMachine())
+}
+
+// execute runs the commands and returns the output of the last one.
+func execute(d *debugger.Debugger, commands ...string) string {
+	out := strings.Builder{}
+	for _, command := range commands {
+		out.Reset()
+		d.Execute(command, &out)
+	}
+	return out.String()
+}
+
+func TestDebugger(t *testing.T) {
+	t.Run("Session", func(t *testing.T) {
+		t.Log("should read commands until quit, repeating the previous command on empty lines")
+
+		d := createDebugger(t)
+		out := strings.Builder{}
+		err := d.Run(strings.NewReader("info\nstep\n\nstep 3\ntape 2\nquit\nstep\n"), &out)
+		if assert.NoError(t, err) {
+			assert.Equal(t, ""+
+				"(turing) step 0: state get1, position 0, symbol 0\n"+
+				"(turing) step 1: state get1, position 1, symbol 1\n"+
+				"(turing) step 2: state get0, position 2, symbol 0\n"+
+				"(turing) step 5: state get0, position 3, symbol 1\n"+
+				"(turing)  1: 0\n"+
+				" 2: <nil>\n"+
+				"[3: 1]\n"+
+				" 4: 0\n"+
+				" 5: 1\n"+
+				"(turing) ",
+				out.String())
+		}
+		assert.Equal(t, 5, d.Machine.Steps())
+	})
+
+	t.Run("EndOfInput", func(t *testing.T) {
+		t.Log("should stop at the end of the input")
+
+		d := createDebugger(t)
+		out := strings.Builder{}
+		if assert.NoError(t, d.Run(strings.NewReader("step"), &out)) {
+			assert.Equal(t, "(turing) step 1: state get1, position 1, symbol 1\n(turing) \n", out.String())
+		}
+	})
+
+	t.Run("Breakpoints", func(t *testing.T) {
+		t.Log("should stop before executing on the breakpoints")
+
+		tests := []struct {
+			command string
+			out     string
+		}{
+			{"break state back0", "breakpoint 1: state back0\nstep 3: state back0, position 1, symbol <nil>\n"},
+			{"break symbol 1", "breakpoint 1: symbol 1\nstep 1: state get1, position 1, symbol 1\n"},
+			{"break symbol _", "breakpoint 1: symbol <nil>\nstep 3: state back0, position 1, symbol <nil>\n"},
+			{"break at get0 1", "breakpoint 1: state get0 and symbol 1\nstep 5: state get0, position 3, symbol 1\n"},
+			{"break pos 4", "breakpoint 1: position 4\nstep 6: state get0, position 4, symbol 0\n"},
+		}
+		for _, tt := range tests {
+			d := createDebugger(t)
+			assert.Equal(t, tt.out, execute(d, tt.command, "continue"), tt.command)
+		}
+	})
+
+	t.Run("ContinueFromBreakpoint", func(t *testing.T) {
+		t.Log("should not stop on the breakpoint the machine is on when continuing")
+
+		d := createDebugger(t)
+		assert.Equal(t,
+			"breakpoint 1: state back0\nstep 7: state back0, position 3, symbol 1\n",
+			execute(d, "break state back0", "continue", "continue"))
+	})
+
+	t.Run("Watchpoint", func(t *testing.T) {
+		t.Log("should stop after the watched cell changes")
+
+		d := createDebugger(t)
+		assert.Equal(t,
+			"watchpoint 1: position 2: 0 -> 1\nstep 3: state back0, position 1, symbol <nil>\n",
+			execute(d, "watch 2", "continue"))
+		assert.Equal(t,
+			"watchpoint 1: position 2: 1 -> <nil>\nstep 5: state get0, position 3, symbol 1\n",
+			execute(d, "continue"))
+	})
+
+	t.Run("Until", func(t *testing.T) {
+		t.Log("should run until the machine is on the state")
+
+		d := createDebugger(t)
+		assert.Equal(t, "step 3: state back0, position 1, symbol <nil>\n", execute(d, "until back0"))
+		assert.Equal(t, "step 33: state [halt], position 4, symbol 1\n", execute(d, "until halt"))
+		assert.Equal(t, "machine is halted\nstep 33: state [halt], position 4, symbol 1\n", execute(d, "step"))
+	})
+
+	t.Run("Back", func(t *testing.T) {
+		t.Log("should go back to the last step on the state")
+
+		d := createDebugger(t)
+		execute(d, "until halt")
+		assert.Equal(t, "step 19: state back0, position 3, symbol <nil>\n", execute(d, "back back0"))
+		assert.Equal(t, debugger.Stop{Reason: debugger.StateReached}, d.LastStop())
+		assert.Equal(t, "step 7: state back0, position 3, symbol 1\n", execute(d, "back back0", "back back0", "back back0", "back back0", "back back0", "back back0"))
+		assert.Equal(t, "step 3: state back0, position 1, symbol <nil>\n", execute(d, "back back0"))
+		assert.Equal(t, "error: no recorded step on state back0\n", execute(d, "back back0"))
+		assert.Equal(t, 3, d.Machine.Steps())
+		assert.Equal(t, "step 33: state [halt], position 4, symbol 1\n", execute(d, "until halt"))
+		assert.Equal(t, "error: usage: back STATE\n", execute(d, "back"))
+	})
+
+	t.Run("StepPoints", func(t *testing.T) {
+		t.Log("should stop stepping on breakpoints and watchpoints")
+
+		d := createDebugger(t)
+		assert.Equal(t, "breakpoint 1: state back0\nstep 3: state back0, position 1, symbol <nil>\n", execute(d, "break state back0", "step 10"))
+		assert.Equal(t, debugger.Stop{Reason: debugger.Breakpoint}, d.LastStop())
+
+		d = createDebugger(t)
+		assert.Equal(t, "watchpoint 1: position 2: 0 -> 1\nstep 3: state back0, position 1, symbol <nil>\n", execute(d, "watch 2", "step 10"))
+		assert.Equal(t, debugger.Stop{Reason: debugger.Watchpoint}, d.LastStop())
+	})
+
+	t.Run("LastStop", func(t *testing.T) {
+		t.Log("should tell why the last command stopped")
+
+		d := createDebugger(t)
+		assert.Equal(t, debugger.Stop{Reason: debugger.Paused}, d.LastStop())
+		execute(d, "step 2")
+		assert.Equal(t, debugger.Stop{Reason: debugger.Paused}, d.LastStop())
+		execute(d, "until back0")
+		assert.Equal(t, debugger.Stop{Reason: debugger.StateReached}, d.LastStop())
+		execute(d, "continue")
+		assert.Equal(t, debugger.Stop{Reason: debugger.MachineStopped, Run: turing.Halted}, d.LastStop())
+
+		d = createDebugger(t)
+		d.MaxSteps = 4
+		execute(d, "continue")
+		assert.Equal(t, debugger.Stop{Reason: debugger.MachineStopped, Run: turing.BudgetExhausted}, d.LastStop())
+
+		d = createDebugger(t)
+		d.Machine.State = turing.State{Name: "missing"}
+		execute(d, "step")
+		stop := d.LastStop()
+		assert.Equal(t, debugger.MachineStopped, stop.Reason)
+		assert.Equal(t, turing.NoOperation, stop.Run)
+		assert.True(t, errors.Is(stop.Err, turing.ErrNoStateOp))
+	})
+
+	t.Run("MaxSteps", func(t *testing.T) {
+		t.Log("should stop continuing after the maximum number of steps")
+
+		d := createDebugger(t)
+		d.MaxSteps = 4
+		assert.Equal(t, "stopped after 4 steps\nstep 4: state get1, position 2, symbol 1\n", execute(d, "continue"))
+	})
+
+	t.Run("NoOperation", func(t *testing.T) {
+		t.Log("should stop when there is no operation to execute")
+
+		d := createDebugger(t)
+		d.Machine.State = turing.State{Name: "missing"}
+		assert.Equal(t,
+			"error: no operation for state missing\nstep 0: state missing, position 0, symbol 0\n",
+			execute(d, "continue"))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Log("should list and delete breakpoints and watchpoints")
+
+		d := createDebugger(t)
+		assert.Equal(t, "no breakpoints or watchpoints\n", execute(d, "list"))
+		assert.Equal(t,
+			"breakpoint 1: state back0\nwatchpoint 2: position 3\nbreakpoint 3: symbol 1\n",
+			execute(d, "b state back0", "w 3", "b symbol 1", "l"))
+		assert.Equal(t, "deleted watchpoint 2: position 3\n", execute(d, "delete 2"))
+		assert.Equal(t, "breakpoint 1: state back0\nbreakpoint 3: symbol 1\n", execute(d, "list"))
+		assert.Equal(t, "deleted all breakpoints and watchpoints\n", execute(d, "delete"))
+		assert.Equal(t, "no breakpoints or watchpoints\n", execute(d, "list"))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Log("should report invalid commands")
+
+		tests := []struct {
+			command string
+			err     string
+		}{
+			{"jump", `unknown command "jump", try help`},
+			{"step x", `invalid number of steps "x"`},
+			{"step 0", `invalid number of steps "0"`},
+			{"until", "usage: until STATE"},
+			{"back", "usage: back STATE"},
+			{"break state", "usage: break state STATE | symbol SYMBOL | at STATE SYMBOL | pos POS"},
+			{"break line 1", "usage: break state STATE | symbol SYMBOL | at STATE SYMBOL | pos POS"},
+			{"break pos x", `invalid position "x"`},
+			{"watch", "usage: watch POS"},
+			{"delete 1", "no breakpoint or watchpoint 1"},
+			{"tape -1", `invalid radius "-1"`},
+			{"info now", "unexpected arguments [now]"},
+		}
+		for _, tt := range tests {
+			d := createDebugger(t)
+			assert.Equal(t, "error: "+tt.err+"\n", execute(d, tt.command), tt.command)
+		}
+	})
+}
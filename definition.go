@@ -0,0 +1,111 @@
+package turing
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnknownFormat means LoadDefinition can't choose the format of a
+// definition file by its extension.
+var ErrUnknownFormat = errors.New("unknown definition format")
+
+// Definition is a complete machine definition: the program, the start state
+// and the initial tape.
+type Definition struct {
+	// Program is the machine program.
+	Program *Program
+	// Start is the initial state of the machine.
+	Start State
+	// Tape is the initial content of the tape, starting at TapeOffset.
+	Tape []Symbol
+	// TapeOffset is the position of the first symbol of Tape.
+	TapeOffset int
+	// HeadPos is the initial position of the head.
+	HeadPos int
+}
+
+// NewMachine creates a machine for the definition, with the head attached
+// to a new infinite tape initialized with the definition tape.
+func (d *Definition) NewMachine() *Machine {
+	tape := NewInfiniteTape()
+	tape.Set(d.TapeOffset, d.Tape...)
+
+	head := Head{}
+	head.Attach(tape, d.HeadPos)
+
+	return &Machine{Head: &head, Program: d.Program, State: d.Start}
+}
+
+// LoadDefinition reads a definition file in the format: json, text, morphett
+// or yaml (turingmachine.io). When format is empty, it is chosen by the file
+// extension: .json, .tm or .txt, .morphett and .yaml or .yml, and the error
+// matches ErrUnknownFormat for other extensions.
+func LoadDefinition(path, format string) (*Definition, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			format = "json"
+		case ".tm", ".txt":
+			format = "text"
+		case ".morphett":
+			format = "morphett"
+		case ".yaml", ".yml":
+			format = "yaml"
+		default:
+			return nil, newKindError(ErrUnknownFormat, "unknown definition format for %s", path)
+		}
+	}
+
+	var parse func(io.Reader) (*Definition, error)
+	switch format {
+	case "json":
+		parse = DecodeJSON
+	case "text":
+		parse = ParseText
+	case "morphett":
+		parse = ParseMorphett
+	case "yaml":
+		parse = ParseTuringMachineIO
+	default:
+		return nil, fmt.Errorf("unknown definition format %q", format)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	def, err := parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return def, nil
+}
+
+// states returns all states of the definition, the start state and the
+// states used by the operations, ordered by name.
+func (d *Definition) states() []State {
+	seen := map[State]bool{d.Start: true}
+	states := []State{d.Start}
+	if d.Start == (State{}) {
+		states = states[:0]
+	}
+	if d.Program == nil {
+		return states
+	}
+	for _, op := range d.Program.SortedOps() {
+		for _, s := range []State{op.State, op.NextState} {
+			if !seen[s] {
+				seen[s] = true
+				states = append(states, s)
+			}
+		}
+	}
+	sortStates(states)
+	return states
+}
@@ -0,0 +1,151 @@
+package turing
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diagram is a program state diagram, with the parallel edges merged.
+type diagram struct {
+	states []State
+	ids    map[State]string
+	start  State
+	edges  []diagramEdge
+}
+
+// diagramEdge is a transition between two states, labelled with all
+// operations between them.
+type diagramEdge struct {
+	from   State
+	to     State
+	labels []string
+}
+
+func newDiagram(p *Program, start State) *diagram {
+	def := Definition{Program: p, Start: start}
+	d := diagram{
+		states: def.states(),
+		ids:    make(map[State]string),
+		start:  start,
+	}
+	for i, s := range d.states {
+		d.ids[s] = fmt.Sprintf("s%d", i)
+	}
+
+	type pair struct{ from, to State }
+	edges := make(map[pair]int)
+	for _, op := range p.SortedOps() {
+		key := pair{op.State, op.NextState}
+		i, exists := edges[key]
+		if !exists {
+			i = len(d.edges)
+			edges[key] = i
+			d.edges = append(d.edges, diagramEdge{from: op.State, to: op.NextState})
+		}
+		d.edges[i].labels = append(d.edges[i].labels, diagramLabel(op))
+	}
+	return &d
+}
+
+// diagramLabel returns the "read/write,move" label of an operation, with
+// the symbols written as in the text format.
+func diagramLabel(op Op) string {
+	symbol := func(s Symbol, wildcard Symbol) string {
+		text, err := formatTextSymbol(s, wildcard)
+		if err != nil {
+			return fmt.Sprint(s)
+		}
+		return text
+	}
+	move, ok := textMovements[op.Movement]
+	if !ok {
+		move = op.Movement
+	}
+	return fmt.Sprintf("%s/%s,%s", symbol(op.Symbol, ANY), symbol(op.WriteSymbol, KEEP), move)
+}
+
+// WriteDOT writes the program state diagram in the Graphviz DOT language.
+//
+// Each state is a node, with halting states double circled and an arrow
+// pointing to the start state. Each transition between two states is an
+// edge labelled with the "read/write,move" of its operations, one per line.
+func WriteDOT(w io.Writer, p *Program, start State) error {
+	d := newDiagram(p, start)
+
+	builder := strings.Builder{}
+	builder.WriteString("digraph program {\n")
+	builder.WriteString("  rankdir=LR;\n")
+	builder.WriteString("  node [shape=circle];\n")
+	if start != (State{}) {
+		builder.WriteString("  start [shape=point];\n")
+	}
+	for _, s := range d.states {
+		shape := ""
+		if s.Halt {
+			shape = ", shape=doublecircle"
+		}
+		fmt.Fprintf(&builder, "  %s [label=%s%s];\n", d.ids[s], dotQuote(s.Name), shape)
+	}
+	if start != (State{}) {
+		fmt.Fprintf(&builder, "  start -> %s;\n", d.ids[start])
+	}
+	for _, e := range d.edges {
+		labels := make([]string, len(e.labels))
+		for i, label := range e.labels {
+			labels[i] = dotEscape(label)
+		}
+		fmt.Fprintf(&builder, "  %s -> %s [label=\"%s\"];\n", d.ids[e.from], d.ids[e.to], strings.Join(labels, "\\n"))
+	}
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// WriteMermaid writes the program state diagram as a Mermaid flowchart.
+//
+// Each state is a node, with halting states double circled and an arrow
+// pointing to the start state. Each transition between two states is an
+// edge labelled with the "read/write,move" of its operations, one per line.
+func WriteMermaid(w io.Writer, p *Program, start State) error {
+	d := newDiagram(p, start)
+
+	builder := strings.Builder{}
+	builder.WriteString("flowchart LR\n")
+	if start != (State{}) {
+		builder.WriteString("  start(( ))\n")
+	}
+	for _, s := range d.states {
+		if s.Halt {
+			fmt.Fprintf(&builder, "  %s(((\"%s\")))\n", d.ids[s], mermaidEscape(s.Name))
+		} else {
+			fmt.Fprintf(&builder, "  %s((\"%s\"))\n", d.ids[s], mermaidEscape(s.Name))
+		}
+	}
+	if start != (State{}) {
+		fmt.Fprintf(&builder, "  start --> %s\n", d.ids[start])
+	}
+	for _, e := range d.edges {
+		labels := make([]string, len(e.labels))
+		for i, label := range e.labels {
+			labels[i] = mermaidEscape(label)
+		}
+		fmt.Fprintf(&builder, "  %s -->|\"%s\"| %s\n", d.ids[e.from], strings.Join(labels, "<br/>"), d.ids[e.to])
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", " ").Replace(s)
+}
@@ -0,0 +1,86 @@
+package turing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagram(t *testing.T) {
+	right := turing.State{"right", false}
+	carry := turing.State{"carry", false}
+	done := turing.State{"done", true}
+
+	program := turing.Program{}
+	program.AddOp(turing.Op{right, turing.ANY, turing.KEEP, turing.RIGHT, right})
+	program.AddOp(turing.Op{right, nil, turing.KEEP, turing.LEFT, carry})
+	program.AddOp(turing.Op{carry, 1, 0, turing.LEFT, carry})
+	program.AddOp(turing.Op{carry, 0, 1, turing.STAY, done})
+	program.AddOp(turing.Op{carry, nil, 1, turing.STAY, done})
+
+	t.Run("DOT", func(t *testing.T) {
+		t.Log("should write the state diagram in the DOT language, merging parallel edges")
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.WriteDOT(&builder, &program, right)) {
+			assert.Equal(t, ""+
+				"digraph program {\n"+
+				"  rankdir=LR;\n"+
+				"  node [shape=circle];\n"+
+				"  start [shape=point];\n"+
+				"  s0 [label=\"carry\"];\n"+
+				"  s1 [label=\"done\", shape=doublecircle];\n"+
+				"  s2 [label=\"right\"];\n"+
+				"  start -> s2;\n"+
+				"  s0 -> s1 [label=\"_/1,S\\n0/1,S\"];\n"+
+				"  s0 -> s0 [label=\"1/0,L\"];\n"+
+				"  s2 -> s0 [label=\"_/*,L\"];\n"+
+				"  s2 -> s2 [label=\"*/*,R\"];\n"+
+				"}\n",
+				builder.String())
+		}
+	})
+
+	t.Run("Mermaid", func(t *testing.T) {
+		t.Log("should write the state diagram as a Mermaid flowchart, merging parallel edges")
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.WriteMermaid(&builder, &program, right)) {
+			assert.Equal(t, ""+
+				"flowchart LR\n"+
+				"  start(( ))\n"+
+				"  s0((\"carry\"))\n"+
+				"  s1(((\"done\")))\n"+
+				"  s2((\"right\"))\n"+
+				"  start --> s2\n"+
+				"  s0 -->|\"_/1,S<br/>0/1,S\"| s1\n"+
+				"  s0 -->|\"1/0,L\"| s0\n"+
+				"  s2 -->|\"_/*,L\"| s0\n"+
+				"  s2 -->|\"*/*,R\"| s2\n",
+				builder.String())
+		}
+	})
+
+	t.Run("Escape", func(t *testing.T) {
+		t.Log("should escape state names and symbols, and omit an unset start state")
+
+		quoted := turing.State{`say "hi"`, false}
+		escaped := turing.Program{}
+		escaped.AddOp(turing.Op{quoted, `a"b`, turing.KEEP, turing.RIGHT, quoted})
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.WriteDOT(&builder, &escaped, turing.State{})) {
+			assert.NotContains(t, builder.String(), "start")
+			assert.Contains(t, builder.String(), `s0 [label="say \"hi\""];`)
+			assert.Contains(t, builder.String(), `s0 -> s0 [label="\"a\\\"b\"/*,R"];`)
+		}
+
+		builder.Reset()
+		if assert.NoError(t, turing.WriteMermaid(&builder, &escaped, turing.State{})) {
+			assert.NotContains(t, builder.String(), "start")
+			assert.Contains(t, builder.String(), `s0(("say #quot;hi#quot;"))`)
+		}
+	})
+}
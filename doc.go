@@ -7,15 +7,23 @@
 // To run a program, you attach the head to a pre initialized tape and
 // executes the program given the initial state of the machine.
 // Each position of the tape can have a symbol or be blank (nil).
+// NewInfiniteTape keeps the cells on two slices growing left and right,
+// NewSparseTape keeps only the pages with symbols, and NewBoundedTape and
+// NewLeftBoundedTape limit where the head goes.
 //
 // The machine tries to find the operation that should be executed
 // for the current state and current symbol under the head.
 //
 // Each operation can change the current symbol, then move the head left or right,
 // and change the current machine state after the head moves. It repeats this
-// until it finds a halting state.
+// until it finds a halting state. RunContext limits the run with a context
+// and a maximum number of steps, for programs that may never halt.
 //
 // To simplify the turing program, operations can have some special definitions,
 // it can match ANY symbol or KEEP the current symbol. The head also can STAY
 // on the same position.
+//
+// The package also has multi-tape and nondeterministic machines, step
+// observers, loop detection, reverse execution, snapshots, state and
+// space-time diagrams, compiled programs, recognizers and computations.
 package turing
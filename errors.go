@@ -0,0 +1,110 @@
+package turing
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the machines, usable with errors.Is. Step errors of a
+// Machine are *StepError values that match one of them.
+var (
+	// ErrHalted means the machine is on a halting state and can't step.
+	ErrHalted = errors.New("machine is halted")
+	// ErrNoStateOp means the program has no operation for the state.
+	ErrNoStateOp = errors.New("no operation for state")
+	// ErrNoSymbolOp means the program has operations for the state, but not
+	// for the symbol under the head.
+	ErrNoSymbolOp = errors.New("no operation for state and symbol")
+	// ErrTapeRead means the tape failed to read the symbol under the head.
+	ErrTapeRead = errors.New("tape read failure")
+	// ErrTapeWrite means the tape failed to write the symbol under the head.
+	ErrTapeWrite = errors.New("tape write failure")
+	// ErrOutOfBounds means the head went outside of a bounded tape. The
+	// error is also an *OutOfBoundsError.
+	ErrOutOfBounds = errors.New("out of the tape bounds")
+	// ErrLooping means the loop detector found a cycle, the machine never
+	// halts. The error is also a *LoopError.
+	ErrLooping = errors.New("machine is looping")
+	// ErrNotHalted means the machine did not halt within the step budget of
+	// a Computation.
+	ErrNotHalted = errors.New("machine did not halt")
+)
+
+// kindError is an error that matches one of the sentinel errors, keeping its
+// own message and the error that caused it.
+type kindError struct {
+	kind  error
+	msg   string
+	cause error
+}
+
+// newKindError creates an error of the kind with a formatted message.
+func newKindError(kind error, format string, a ...interface{}) error {
+	return &kindError{kind: kind, msg: fmt.Sprintf(format, a...)}
+}
+
+// wrapKindError creates an error of the kind caused by err, with the err
+// message.
+func wrapKindError(kind error, err error) error {
+	return &kindError{kind: kind, msg: err.Error(), cause: err}
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *kindError) Unwrap() error {
+	return e.cause
+}
+
+// LoopError is the error of runs stopped by the loop detector. It matches
+// ErrLooping.
+type LoopError struct {
+	// Cycle is the cycle found.
+	Cycle Cycle
+}
+
+func (e *LoopError) Error() string {
+	return fmt.Sprintf("machine is looping, %v", e.Cycle)
+}
+
+// Is tells if the target is ErrLooping.
+func (e *LoopError) Is(target error) bool {
+	return target == ErrLooping
+}
+
+// StepError is the error of a Machine or MultiMachine step. It has the
+// machine configuration where the step failed, and wraps the failure, so
+// errors.Is matches the sentinel errors and errors.As the tape errors.
+type StepError struct {
+	// Step is the number of the step that failed, the first step of a
+	// machine is 1.
+	Step int
+	// State is the machine state.
+	State State
+	// Symbol is the symbol under the head, nil when it could not be read.
+	Symbol Symbol
+	// Pos is the head position.
+	Pos int
+	// Symbols are the symbols under the heads of a MultiMachine, nil when
+	// they could not be read.
+	Symbols []Symbol
+	// Positions are the head positions of a MultiMachine.
+	Positions []int
+	// Err is the failure.
+	Err error
+}
+
+// Error returns the failure message.
+func (e *StepError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the failure.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
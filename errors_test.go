@@ -0,0 +1,192 @@
+package turing_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+// failTape is a tape that fails to read or write on some positions.
+type failTape struct {
+	mapTape
+	failRead  int
+	failWrite int
+}
+
+func (t failTape) Get(pos int) (turing.Symbol, error) {
+	if pos == t.failRead {
+		return nil, fmt.Errorf("can't read position %d", pos)
+	}
+	return t.mapTape.Get(pos)
+}
+
+func (t failTape) Set(pos int, symbols ...turing.Symbol) error {
+	if pos == t.failWrite {
+		return fmt.Errorf("can't write position %d", pos)
+	}
+	return t.mapTape.Set(pos, symbols...)
+}
+
+func TestStepErrors(t *testing.T) {
+	state := turing.State{"state", false}
+	other := turing.State{"other", false}
+	halt := turing.State{"halt", true}
+
+	program := turing.Program{}
+	program.AddOp(turing.Op{state, 0, 1, turing.RIGHT, state})
+	program.AddOp(turing.Op{state, 1, turing.KEEP, turing.LEFT, state})
+	program.AddOp(turing.Op{state, 2, 0, turing.STAY, other})
+
+	t.Run("Step", func(t *testing.T) {
+		t.Log("should return typed errors with the machine configuration")
+
+		bounded := turing.NewLeftBoundedTape(5, false)
+		bounded.Set(5, 1)
+
+		tests := []struct {
+			name   string
+			state  turing.State
+			tape   turing.Tape
+			pos    int
+			kind   error
+			symbol turing.Symbol
+			err    string
+		}{
+			{
+				name:  "Halted",
+				state: halt,
+				tape:  mapTape{},
+				kind:  turing.ErrHalted,
+				err:   "machine is halted, state [halt]",
+			},
+			{
+				name:   "NoStateOp",
+				state:  other,
+				tape:   mapTape{5: 0},
+				pos:    5,
+				kind:   turing.ErrNoStateOp,
+				symbol: 0,
+				err:    "no operation for state other",
+			},
+			{
+				name:   "NoSymbolOp",
+				state:  state,
+				tape:   mapTape{5: 3},
+				pos:    5,
+				kind:   turing.ErrNoSymbolOp,
+				symbol: 3,
+				err:    "no operation for state state and symbol 3",
+			},
+			{
+				name:  "TapeRead",
+				state: state,
+				tape:  failTape{mapTape: mapTape{}, failRead: 5},
+				pos:   5,
+				kind:  turing.ErrTapeRead,
+				err:   "can't read position 5",
+			},
+			{
+				name:   "TapeWrite",
+				state:  state,
+				tape:   failTape{mapTape: mapTape{5: 0}, failWrite: 5},
+				pos:    5,
+				kind:   turing.ErrTapeWrite,
+				symbol: 0,
+				err:    "can't write position 5",
+			},
+			{
+				name:   "OutOfBounds",
+				state:  state,
+				tape:   bounded,
+				pos:    5,
+				kind:   turing.ErrOutOfBounds,
+				symbol: 1,
+				err:    "position 4 is out of the tape bounds",
+			},
+		}
+
+		for _, tt := range tests {
+			head := turing.Head{}
+			head.Attach(tt.tape, tt.pos)
+			machine := turing.Machine{Head: &head, Program: &program, State: tt.state}
+
+			err := machine.Step()
+			assert.EqualError(t, err, tt.err, tt.name)
+			assert.True(t, errors.Is(err, tt.kind), "%s: error should match %v", tt.name, tt.kind)
+
+			var stepErr *turing.StepError
+			if assert.True(t, errors.As(err, &stepErr), tt.name) {
+				assert.Equal(t, 1, stepErr.Step, tt.name)
+				assert.Equal(t, tt.state, stepErr.State, tt.name)
+				assert.Equal(t, tt.symbol, stepErr.Symbol, tt.name)
+				assert.Equal(t, tt.pos, stepErr.Pos, tt.name)
+			}
+		}
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		t.Log("should wrap the step error on Run")
+
+		head := turing.Head{}
+		head.Attach(turing.NewBoundedTape(0, 3, nil, nil), 0)
+		head.Write(0)
+		machine := turing.Machine{Head: &head, Program: &program, State: state}
+
+		err := machine.Run()
+		assert.EqualError(t, err, "Error at instruction 2: no operation for state state and symbol <nil>")
+		assert.True(t, errors.Is(err, turing.ErrNoSymbolOp))
+
+		var stepErr *turing.StepError
+		if assert.True(t, errors.As(err, &stepErr)) {
+			assert.Equal(t, turing.StepError{Step: 2, State: state, Symbol: nil, Pos: 1, Err: stepErr.Err}, *stepErr)
+		}
+	})
+
+	t.Run("OutOfBoundsRead", func(t *testing.T) {
+		t.Log("should match tape read and out of bounds errors")
+
+		head := turing.Head{}
+		head.Attach(turing.NewBoundedTape(0, 3, nil, nil), 7)
+		machine := turing.Machine{Head: &head, Program: &program, State: state}
+
+		err := machine.Step()
+		assert.True(t, errors.Is(err, turing.ErrTapeRead))
+		assert.True(t, errors.Is(err, turing.ErrOutOfBounds))
+		var oob *turing.OutOfBoundsError
+		if assert.True(t, errors.As(err, &oob)) {
+			assert.Equal(t, 7, oob.Pos)
+		}
+	})
+
+	t.Run("Programs", func(t *testing.T) {
+		t.Log("should match the sentinel errors when programs have no operation")
+
+		_, err := program.FindOp(other, 0)
+		assert.True(t, errors.Is(err, turing.ErrNoStateOp))
+		_, err = program.FindOp(state, 5)
+		assert.True(t, errors.Is(err, turing.ErrNoSymbolOp))
+		assert.False(t, errors.Is(err, turing.ErrNoStateOp))
+
+		nondeterministic := turing.NondeterministicProgram{}
+		nondeterministic.AddOp(turing.Op{state, 0, 1, turing.RIGHT, state})
+		_, err = nondeterministic.FindOps(other, 0)
+		assert.True(t, errors.Is(err, turing.ErrNoStateOp))
+		_, err = nondeterministic.FindOps(state, 5)
+		assert.True(t, errors.Is(err, turing.ErrNoSymbolOp))
+
+		multi := turing.MultiProgram{}
+		multi.AddOp(turing.MultiOp{state, []turing.Symbol{0}, []turing.Symbol{1}, []string{turing.RIGHT}, state})
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+		machine := turing.MultiMachine{Heads: []*turing.Head{&head}, Program: &multi, State: state}
+		err = machine.Run()
+		assert.EqualError(t, err, "Error at instruction 1: no operation for state state and symbols [<nil>]")
+		assert.True(t, errors.Is(err, turing.ErrNoSymbolOp))
+
+		machine.State = halt
+		assert.True(t, errors.Is(machine.Step(), turing.ErrHalted))
+	})
+}
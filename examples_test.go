@@ -2,13 +2,14 @@ package turing_test
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/massahud/turing"
 )
 
-// Simple machine that transforms everything in zeros and halt
-// on blank (nil).
-func Example_zeroAll() {
+// createZeroAll creates a program that transforms everything in zeros and
+// halts on blank (nil).
+func createZeroAll() (turing.State, *turing.Program) {
 	// states
 	zeroing := turing.State{"zero all", false}
 	halt := turing.State{"halt", true}
@@ -20,9 +21,17 @@ func Example_zeroAll() {
 	program.AddOp(zeroAll)
 	program.AddOp(finishOnBlank)
 
+	return zeroing, &program
+}
+
+// Simple machine that transforms everything in zeros and halt
+// on blank (nil).
+func Example_zeroAll() {
+	zeroing, program := createZeroAll()
+
 	head := turing.Head{}
 
-	machine := turing.Machine{Head: &head, Program: &program, State: zeroing}
+	machine := turing.Machine{Head: &head, Program: program, State: zeroing}
 
 	tape := turing.NewInfiniteTape()
 	tape.Set(0, 1, 1, 1, 0, 1, 1, 0, 1, 0, 1)
@@ -45,8 +54,8 @@ func Example_zeroAll() {
 	// Tape[0,10]: 0 0 0 0 0 0 0 0 0 0 <nil>
 }
 
-// Mirrors a [01]* string.
-func Example_mirror() {
+// createMirror creates a program that mirrors a [01]* string.
+func createMirror() (turing.State, *turing.Program) {
 	// states
 	// goes to end
 	toEnd := turing.State{"toEnd0", false}
@@ -82,9 +91,16 @@ func Example_mirror() {
 	program.AddOp(turing.Op{return1, nil, 1, turing.LEFT, cut})
 	program.AddOp(turing.Op{return1, turing.ANY, turing.KEEP, turing.LEFT, return1})
 
+	return toEnd, &program
+}
+
+// Mirrors a [01]* string.
+func Example_mirror() {
+	toEnd, program := createMirror()
+
 	head := turing.Head{}
 
-	machine := turing.Machine{Head: &head, Program: &program, State: toEnd}
+	machine := turing.Machine{Head: &head, Program: program, State: toEnd}
 
 	tape := turing.NewInfiniteTape()
 	tape.Set(0, 1, 0, 1)
@@ -111,10 +127,9 @@ func Example_mirror() {
 	// Tape[-1,5]: <nil> 1 0 1 1 0 1
 }
 
-// Copies a [01]* string, leaving a blank between the original and the copy.
-// Head stops in the middle blank position
-func Example_copy() {
-
+// createCopy creates a program that copies a [01]* string, leaving a blank
+// between the original and the copy.
+func createCopy() (turing.State, *turing.Program) {
 	// states
 	// cut current symbol of source sequence
 	cut := turing.State{"copy", false}
@@ -162,9 +177,17 @@ func Example_copy() {
 	program.AddOp(turing.Op{return1, nil, 1, turing.RIGHT, cut})
 	program.AddOp(turing.Op{return1, turing.ANY, turing.KEEP, turing.LEFT, return1})
 
+	return cut, &program
+}
+
+// Copies a [01]* string, leaving a blank between the original and the copy.
+// Head stops in the middle blank position
+func Example_copy() {
+	cut, program := createCopy()
+
 	head := turing.Head{}
 
-	machine := turing.Machine{Head: &head, Program: &program, State: cut}
+	machine := turing.Machine{Head: &head, Program: program, State: cut}
 
 	tape := turing.NewInfiniteTape()
 	tape.Set(0, 1, 1, 1, 0, 1, 1, 0, 1, 0, 1)
@@ -189,3 +212,63 @@ func Example_copy() {
 	// State: [halt]
 	// Tape[0,20]: 1 1 1 0 1 1 0 1 0 1 <nil> 1 1 1 0 1 1 0 1 0 1
 }
+
+// mirrorText is the mirror program in the text format.
+const mirrorText = `
+	start toEnd
+	halt halt
+
+	# goes to end
+	toEnd _ -> * L cut
+	toEnd * -> * R toEnd
+
+	# cut current symbol of source sequence
+	cut _ -> * R halt
+	cut 0 -> _ R paste0
+	cut 1 -> _ R paste1
+
+	# pastes one of the symbols at the end
+	paste0 _ -> 0 S return0
+	paste0 * -> * R paste0
+	paste1 _ -> 1 S return1
+	paste1 * -> * R paste1
+
+	# goes to where it cut the sequence to fix it
+	return0 _ -> 0 L cut
+	return0 * -> * L return0
+	return1 _ -> 1 L cut
+	return1 * -> * L return1
+
+	tape 1 0 1
+`
+
+// Mirrors a [01]* string, with the program written in the text format.
+func Example_textMirror() {
+	def, err := turing.ParseText(strings.NewReader(mirrorText))
+	if err != nil {
+		fmt.Println("error:", err.Error())
+		return
+	}
+
+	machine := def.NewMachine()
+	err = machine.Run()
+	if err != nil {
+		fmt.Println("error:", err.Error())
+	}
+
+	head := machine.Head
+	fmt.Println("Head:", head.Pos())
+	fmt.Println("State:", machine.State)
+	fmt.Print(head.PrintTape(head.MinPos(), head.MaxPos()))
+
+	// Output:
+	// Head: 0
+	// State: [halt]
+	//  -1: <nil>
+	// [0: 1]
+	//  1: 0
+	//  2: 1
+	//  3: 1
+	//  4: 0
+	//  5: 1
+}
@@ -0,0 +1,340 @@
+package turing
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// GoOptions configures the Go source written by WriteGo.
+type GoOptions struct {
+	// Package is the package of the generated file. It is "main" when not
+	// set.
+	Package string
+	// Name prefixes the generated identifiers, it must be an exported Go
+	// identifier. It is "Program" when not set.
+	Name string
+	// Source is the name of the definition file, written on the generated
+	// code header.
+	Source string
+}
+
+// WriteGo writes Go source code that runs the definition program, with a
+// switch per state and symbol operating on a tape of symbol indexes. For the
+// Name "Program", it declares:
+//
+//	var ProgramSymbols = []interface{}{nil, ...}  // symbol of each index
+//	var ProgramStates = []string{...}             // state names
+//	var ProgramHalting = []bool{...}              // halting states
+//	const ProgramStart = ...                      // start state index
+//	type ProgramMachine struct {
+//		State  int    // state index
+//		Tape   []byte // symbol index of each cell, []int for more than 256 symbols
+//		Offset int    // Tape[i] is position i-Offset
+//		Pos    int    // head position
+//		Steps  int    // executed steps
+//	}
+//	func NewProgramMachine(tape []interface{}, offset, pos int) (*ProgramMachine, error)
+//	func (m *ProgramMachine) Run(maxSteps int) error
+//	func (m *ProgramMachine) Halted() bool
+//	func (m *ProgramMachine) Symbol(pos int) interface{}
+//
+// Run behaves like Machine.Run, with the same error messages, but it also
+// returns nil after maxSteps steps, when maxSteps is positive. Symbols must
+// be nil, integers or strings, and the tapes can only have symbols used by
+// the definition.
+func WriteGo(w io.Writer, d *Definition, opts GoOptions) error {
+	if opts.Package == "" {
+		opts.Package = "main"
+	}
+	if opts.Name == "" {
+		opts.Name = "Program"
+	}
+	if !token.IsIdentifier(opts.Package) {
+		return fmt.Errorf("invalid package name %q", opts.Package)
+	}
+	if !token.IsIdentifier(opts.Name) || !token.IsExported(opts.Name) {
+		return fmt.Errorf("name %q is not an exported identifier", opts.Name)
+	}
+
+	g, err := newGoGenerator(d, opts)
+	if err != nil {
+		return err
+	}
+	source, err := format.Source(g.generate())
+	if err != nil {
+		return fmt.Errorf("formatting generated code: %s", err.Error())
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// goGenerator writes the Go source of a definition.
+type goGenerator struct {
+	def       *Definition
+	opts      GoOptions
+	states    []State
+	stateIDs  map[State]int
+	symbols   []Symbol
+	symbolIDs map[Symbol]int
+	cell      string
+	buff      bytes.Buffer
+}
+
+func newGoGenerator(d *Definition, opts GoOptions) (*goGenerator, error) {
+	g := &goGenerator{
+		def:       d,
+		opts:      opts,
+		states:    d.states(),
+		stateIDs:  make(map[State]int),
+		symbols:   []Symbol{nil},
+		symbolIDs: map[Symbol]int{nil: 0},
+		cell:      "byte",
+	}
+	for i, s := range g.states {
+		g.stateIDs[s] = i
+	}
+
+	var symbols []Symbol
+	seen := map[Symbol]bool{nil: true, ANY: true}
+	add := func(s Symbol) {
+		if !seen[s] {
+			seen[s] = true
+			symbols = append(symbols, s)
+		}
+	}
+	for _, op := range d.Program.ListOps() {
+		add(op.Symbol)
+		if op.WriteSymbol != KEEP {
+			add(op.WriteSymbol)
+		}
+	}
+	for _, s := range d.Tape {
+		add(s)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return compareSymbols(symbols[i], symbols[j]) < 0
+	})
+	for _, s := range symbols {
+		if _, err := goSymbol(s); err != nil {
+			return nil, err
+		}
+		g.symbolIDs[s] = len(g.symbols)
+		g.symbols = append(g.symbols, s)
+	}
+	if len(g.symbols) > 256 {
+		g.cell = "int"
+	}
+	return g, nil
+}
+
+// goSymbol returns the Go literal of the symbol.
+func goSymbol(s Symbol) (string, error) {
+	switch v := s.(type) {
+	case nil:
+		return "nil", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case string:
+		return strconv.Quote(v), nil
+	}
+	return "", fmt.Errorf("symbol %#v is not supported", s)
+}
+
+func (g *goGenerator) printf(format string, a ...interface{}) {
+	fmt.Fprintf(&g.buff, format, a...)
+}
+
+// generate returns the unformatted source.
+func (g *goGenerator) generate() []byte {
+	name := g.opts.Name
+	source := ""
+	if g.opts.Source != "" {
+		source = " from " + g.opts.Source
+	}
+	g.printf("// Code generated by turinggen%s. DO NOT EDIT.\n\n", source)
+	g.printf("package %s\n\n", g.opts.Package)
+	g.printf("import \"fmt\"\n\n")
+
+	g.printf("// %sSymbols are the symbols of the tape cells, each cell has the index of\n", name)
+	g.printf("// its symbol and 0 is the blank symbol.\n")
+	g.printf("var %sSymbols = []interface{}{", name)
+	for i, s := range g.symbols {
+		literal, _ := goSymbol(s)
+		if i > 0 {
+			g.printf(", ")
+		}
+		g.printf("%s", literal)
+	}
+	g.printf("}\n\n")
+
+	g.printf("// %sStates are the names of the states.\n", name)
+	g.printf("var %sStates = []string{", name)
+	for i, s := range g.states {
+		if i > 0 {
+			g.printf(", ")
+		}
+		g.printf("%s", strconv.Quote(s.Name))
+	}
+	g.printf("}\n\n")
+
+	g.printf("// %sHalting tells which states are halting states.\n", name)
+	g.printf("var %sHalting = []bool{", name)
+	for i, s := range g.states {
+		if i > 0 {
+			g.printf(", ")
+		}
+		g.printf("%t", s.Halt)
+	}
+	g.printf("}\n\n")
+
+	g.printf("// %sStart is the index of the start state, %v.\n", name, g.def.Start)
+	g.printf("const %sStart = %d\n\n", name, g.stateIDs[g.def.Start])
+
+	g.printf(`// %[1]sMachine is a configuration of the machine.
+type %[1]sMachine struct {
+	// State is the index of the machine state.
+	State int
+	// Tape has the symbol index of the cells, Tape[i] is position i-Offset.
+	Tape []%[2]s
+	// Offset is the number of cells before position 0.
+	Offset int
+	// Pos is the head position.
+	Pos int
+	// Steps is the number of executed steps.
+	Steps int
+}
+
+// New%[1]sMachine creates a machine on the start state, with the tape symbols
+// from position offset on and the head on position pos.
+func New%[1]sMachine(tape []interface{}, offset, pos int) (*%[1]sMachine, error) {
+	m := &%[1]sMachine{State: %[1]sStart, Tape: make([]%[2]s, len(tape)), Offset: -offset, Pos: pos}
+	for i, s := range tape {
+		index := -1
+		for j, symbol := range %[1]sSymbols {
+			if symbol == s {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("symbol %%v is not used by the program", s)
+		}
+		m.Tape[i] = %[2]s(index)
+	}
+	return m, nil
+}
+
+// Halted tells if the machine is on a halting state.
+func (m *%[1]sMachine) Halted() bool {
+	return %[1]sHalting[m.State]
+}
+
+// Symbol returns the symbol on the position.
+func (m *%[1]sMachine) Symbol(pos int) interface{} {
+	i := pos + m.Offset
+	if i < 0 || i >= len(m.Tape) {
+		return nil
+	}
+	return %[1]sSymbols[m.Tape[i]]
+}
+
+// Run executes the program until it reaches a halt state or there is no
+// operation for the current state and symbol. When maxSteps is positive, it
+// also stops after maxSteps steps.
+func (m *%[1]sMachine) Run(maxSteps int) error {
+	tape, offset, pos, state, steps := m.Tape, m.Offset, m.Pos, m.State, m.Steps
+	defer func() {
+		m.Tape, m.Offset, m.Pos, m.State, m.Steps = tape, offset, pos, state, steps
+	}()
+
+	for instr := 1; maxSteps < 1 || instr <= maxSteps; instr++ {
+		i := pos + offset
+		if i < 0 {
+			grown := make([]%[2]s, 2*len(tape)-i)
+			copy(grown[len(grown)-len(tape):], tape)
+			offset += len(grown) - len(tape)
+			tape = grown
+			i = pos + offset
+		}
+		for i >= len(tape) {
+			tape = append(tape, 0)
+		}
+
+		switch state {
+`, name, g.cell)
+
+	for id, s := range g.states {
+		g.printf("case %d: // %v\n", id, s)
+		g.generateState(s)
+	}
+	g.printf(`default:
+			return fmt.Errorf("invalid state %%d", state)
+		}
+		steps++
+	}
+	return nil
+}
+`)
+	return g.buff.Bytes()
+}
+
+// generateState writes the switch case of a state.
+func (g *goGenerator) generateState(s State) {
+	name := g.opts.Name
+	if s.Halt {
+		g.printf("return nil\n")
+		return
+	}
+
+	var ops []Op
+	var anyOp *Op
+	for _, op := range g.def.Program.SortedOps() {
+		if op.State != s {
+			continue
+		}
+		if op.Symbol == ANY {
+			op := op
+			anyOp = &op
+			continue
+		}
+		ops = append(ops, op)
+	}
+	if len(ops) == 0 && anyOp == nil {
+		g.printf("return fmt.Errorf(\"Error at instruction %%d: no operation for state %%s\", instr, %s)\n", strconv.Quote(s.String()))
+		return
+	}
+
+	g.printf("switch tape[i] {\n")
+	for _, op := range ops {
+		g.printf("case %d: // %s\n", g.symbolIDs[op.Symbol], diagramLabel(op))
+		g.generateOp(op)
+	}
+	g.printf("default:\n")
+	if anyOp != nil {
+		g.printf("// %s\n", diagramLabel(*anyOp))
+		g.generateOp(*anyOp)
+	} else {
+		g.printf("return fmt.Errorf(\"Error at instruction %%d: no operation for state %%s and symbol %%v\", instr, %s, %sSymbols[tape[i]])\n",
+			strconv.Quote(s.String()), name)
+	}
+	g.printf("}\n")
+}
+
+// generateOp writes the statements of an operation.
+func (g *goGenerator) generateOp(op Op) {
+	if op.WriteSymbol != KEEP {
+		g.printf("tape[i] = %d\n", g.symbolIDs[op.WriteSymbol])
+	}
+	switch op.Movement {
+	case LEFT:
+		g.printf("pos--\n")
+	case RIGHT:
+		g.printf("pos++\n")
+	}
+	g.printf("state = %d\n", g.stateIDs[op.NextState])
+}
@@ -0,0 +1,50 @@
+package turing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteGo(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Log("should write the main package with the Program prefix")
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.WriteGo(&builder, busyBeaver(), turing.GoOptions{})) {
+			source := builder.String()
+			assert.True(t, strings.HasPrefix(source, "// Code generated by turinggen. DO NOT EDIT.\n\npackage main\n"))
+			assert.Contains(t, source, "var ProgramSymbols = []interface{}{nil, 1}\n")
+			assert.Contains(t, source, "var ProgramStates = []string{\"a\", \"b\", \"c\", \"d\", \"halt\"}\n")
+			assert.Contains(t, source, "const ProgramStart = 0\n")
+			assert.Contains(t, source, "\tTape []byte\n")
+		}
+	})
+
+	t.Run("IntTape", func(t *testing.T) {
+		t.Log("should use an int tape for programs with more than 256 symbols")
+
+		state := turing.State{"state", false}
+		program := turing.Program{}
+		for i := 0; i < 300; i++ {
+			program.AddOp(turing.Op{state, i, i + 1, turing.RIGHT, state})
+		}
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.WriteGo(&builder, &turing.Definition{Program: &program, Start: state}, turing.GoOptions{})) {
+			assert.Contains(t, builder.String(), "\tTape []int\n")
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Log("should not write invalid names or unsupported symbols")
+
+		def := busyBeaver()
+		assert.EqualError(t, turing.WriteGo(&strings.Builder{}, def, turing.GoOptions{Package: "my-package"}), `invalid package name "my-package"`)
+		assert.EqualError(t, turing.WriteGo(&strings.Builder{}, def, turing.GoOptions{Name: "beaver"}), `name "beaver" is not an exported identifier`)
+
+		def.Tape = []turing.Symbol{1.5}
+		assert.EqualError(t, turing.WriteGo(&strings.Builder{}, def, turing.GoOptions{}), "symbol 1.5 is not supported")
+	})
+}
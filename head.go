@@ -13,20 +13,40 @@ type Head struct {
 	maxPos int
 }
 
-// Move moves the head or stays at the same place
+// Move moves the head or stays at the same place. On bounded tapes the head
+// stays where it is when it can't move.
 func (h *Head) Move(movement string) {
+	if pos, err := h.destination(movement); err == nil {
+		h.moveTo(pos)
+	}
+}
+
+// destination returns the position where the head goes with the movement, or
+// the out of bounds error when it can't move.
+func (h *Head) destination(movement string) (int, error) {
+	pos := h.pos
 	switch movement {
 	case LEFT:
-		h.pos--
-		if h.pos < h.minPos {
-			h.minPos = h.pos
-		}
+		pos--
 	case RIGHT:
-		h.pos++
-		if h.pos > h.maxPos {
-			h.maxPos = h.pos
-		}
+		pos++
 	default:
+		return pos, nil
+	}
+	if tape, ok := h.tape.(limitedTape); ok {
+		return tape.limit(pos)
+	}
+	return pos, nil
+}
+
+// moveTo moves the head to the position.
+func (h *Head) moveTo(pos int) {
+	h.pos = pos
+	if h.pos < h.minPos {
+		h.minPos = h.pos
+	}
+	if h.pos > h.maxPos {
+		h.maxPos = h.pos
 	}
 }
 
@@ -48,6 +68,15 @@ func (h *Head) Write(s Symbol) error {
 	return h.tape.Set(h.pos, s)
 }
 
+// writable returns the error Write returns when the tape limits where the
+// head goes and can't write the symbol, without writing it.
+func (h *Head) writable(s Symbol) error {
+	if tape, ok := h.tape.(limitedTape); ok {
+		return tape.writable(h.pos, s)
+	}
+	return nil
+}
+
 // Pos returns the head position on the attached tape.
 func (h *Head) Pos() int {
 	return h.pos
@@ -1,27 +1,37 @@
 package turing
 
 // infiniteTape is a turing machine tape with "infinite" left and right
+//
+// The cells are kept on two slices growing away from position 0, so writing
+// on a new position of any side only appends to one of them.
 type infiniteTape struct {
-	buff  []Symbol
-	shift int
+	// right has the cells from position 0 on
+	right []Symbol
+	// left has the cells from position -1 on, going left
+	left []Symbol
 }
 
 // NewInfiniteTape creates a new infinite tape. An infinite tape is 'infinite'
 // in both directions, initialized with the blank symbol (nil) on all positions.
 func NewInfiniteTape() Tape {
-	buff := make([]Symbol, 0)
 	return &infiniteTape{
-		buff:  buff,
-		shift: 0,
+		right: make([]Symbol, 0),
+		left:  make([]Symbol, 0),
 	}
 }
 
 func (t *infiniteTape) Get(pos int) (Symbol, error) {
-	realPos := pos + t.shift
-	if realPos < 0 || realPos >= len(t.buff) {
+	if pos >= 0 {
+		if pos >= len(t.right) {
+			return nil, nil
+		}
+		return t.right[pos], nil
+	}
+	i := -pos - 1
+	if i >= len(t.left) {
 		return nil, nil
 	}
-	return t.buff[realPos], nil
+	return t.left[i], nil
 }
 
 func (t *infiniteTape) Set(pos int, symbols ...Symbol) error {
@@ -29,30 +39,45 @@ func (t *infiniteTape) Set(pos int, symbols ...Symbol) error {
 		return nil
 	}
 
-	start := pos + t.shift
-	end := start + len(symbols)
-
-	var expandLeft int
-	var expandRight int
-	if end >= len(t.buff) {
-		expandRight = end - len(t.buff)
-	}
-	if start < 0 {
-		expandLeft = -start
-		t.shift += -start
-		start = 0
+	end := pos + len(symbols)
+	if end > 0 {
+		start := pos
+		if start < 0 {
+			start = 0
+		}
+		t.right = growCells(t.right, end)
+		copy(t.right[start:], symbols[start-pos:])
 	}
-	if expandLeft > 0 && expandRight > 0 {
-		expandRight += expandLeft
-		expandLeft = 0
+	if pos < 0 {
+		last := end
+		if last > 0 {
+			last = 0
+		}
+		t.left = growCells(t.left, -pos)
+		for p := pos; p < last; p++ {
+			t.left[-p-1] = symbols[p-pos]
+		}
 	}
-	if expandRight > 0 {
-		t.buff = append(t.buff, make([]Symbol, expandRight)...)
-	}
-	if expandLeft > 0 {
-		t.buff = append(t.buff[:0], append(make([]Symbol, expandLeft), t.buff[0:]...)...)
+	return nil
+}
+
+// growCells extends the cells with blank symbols up to the length. append
+// grows the capacity geometrically, so growing one cell at a time is
+// amortized constant time.
+func growCells(cells []Symbol, length int) []Symbol {
+	for len(cells) < length {
+		cells = append(cells, nil)
 	}
+	return cells
+}
 
-	copy(t.buff[start:], symbols)
-	return nil
+// cells returns the cells from the leftmost to the rightmost position ever
+// written, and the number of cells before position 0.
+func (t *infiniteTape) cells() ([]Symbol, int) {
+	cells := make([]Symbol, len(t.left)+len(t.right))
+	for i, s := range t.left {
+		cells[len(t.left)-1-i] = s
+	}
+	copy(cells[len(t.left):], t.right)
+	return cells, len(t.left)
 }
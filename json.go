@@ -0,0 +1,335 @@
+package turing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// defaultWildcard is the wildcard used when a JSON definition does not
+// declare one.
+const defaultWildcard = "*"
+
+type jsonDefinition struct {
+	Start    string      `json:"start"`
+	Wildcard string      `json:"wildcard,omitempty"`
+	States   []jsonState `json:"states"`
+	Ops      []jsonOp    `json:"ops"`
+	Tape     *jsonTape   `json:"tape,omitempty"`
+}
+
+type jsonState struct {
+	Name string `json:"name"`
+	Halt bool   `json:"halt,omitempty"`
+}
+
+type jsonOp struct {
+	State string          `json:"state"`
+	Read  json.RawMessage `json:"read"`
+	Write json.RawMessage `json:"write"`
+	Move  string          `json:"move"`
+	Next  string          `json:"next"`
+}
+
+type jsonTape struct {
+	Offset  int               `json:"offset"`
+	Head    int               `json:"head"`
+	Symbols []json.RawMessage `json:"symbols"`
+}
+
+// DecodeJSON reads a JSON machine definition.
+//
+// The definition is an object with the start state name, the list of
+// states, the operations and an optional initial tape:
+//
+//	{
+//	  "start": "zero all",
+//	  "wildcard": "*",
+//	  "states": [
+//	    {"name": "halt", "halt": true},
+//	    {"name": "zero all"}
+//	  ],
+//	  "ops": [
+//	    {"state": "zero all", "read": null, "write": null, "move": "stay", "next": "halt"},
+//	    {"state": "zero all", "read": "*", "write": 0, "move": "right", "next": "zero all"}
+//	  ],
+//	  "tape": {"offset": 0, "head": 0, "symbols": [1, 0, 1]}
+//	}
+//
+// Every state used by the operations and the start state must be on the
+// states list. The movement is one of "left", "right" or "stay".
+//
+// Symbols are null for the blank symbol, integers or strings. The wildcard
+// string, "*" when omitted, is ANY when it is the read symbol and KEEP when
+// it is the write symbol.
+func DecodeJSON(r io.Reader) (*Definition, error) {
+	var jd jsonDefinition
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&jd); err != nil {
+		return nil, fmt.Errorf("invalid JSON definition: %s", err.Error())
+	}
+
+	wildcard := jd.Wildcard
+	if wildcard == "" {
+		wildcard = defaultWildcard
+	}
+
+	states := make(map[string]State, len(jd.States))
+	for _, s := range jd.States {
+		if _, exists := states[s.Name]; exists {
+			return nil, fmt.Errorf("duplicated state %q", s.Name)
+		}
+		states[s.Name] = State{Name: s.Name, Halt: s.Halt}
+	}
+	state := func(name string) (State, error) {
+		s, ok := states[name]
+		if !ok {
+			return State{}, fmt.Errorf("unknown state %q", name)
+		}
+		return s, nil
+	}
+
+	def := Definition{Program: &Program{}}
+
+	var err error
+	if def.Start, err = state(jd.Start); err != nil {
+		return nil, fmt.Errorf("start: %s", err.Error())
+	}
+
+	for i, jop := range jd.Ops {
+		op := Op{Movement: jop.Move}
+		if op.State, err = state(jop.State); err != nil {
+			return nil, fmt.Errorf("op %d: %s", i, err.Error())
+		}
+		if op.NextState, err = state(jop.Next); err != nil {
+			return nil, fmt.Errorf("op %d: %s", i, err.Error())
+		}
+		if op.Symbol, err = decodeJSONSymbol(jop.Read, wildcard, ANY); err != nil {
+			return nil, fmt.Errorf("op %d: read: %s", i, err.Error())
+		}
+		if op.WriteSymbol, err = decodeJSONSymbol(jop.Write, wildcard, KEEP); err != nil {
+			return nil, fmt.Errorf("op %d: write: %s", i, err.Error())
+		}
+		if !validMovement(op.Movement) {
+			return nil, fmt.Errorf("op %d: unknown movement %q", i, op.Movement)
+		}
+		def.Program.AddOp(op)
+	}
+
+	if jd.Tape != nil {
+		def.TapeOffset = jd.Tape.Offset
+		def.HeadPos = jd.Tape.Head
+		def.Tape = make([]Symbol, len(jd.Tape.Symbols))
+		for i, raw := range jd.Tape.Symbols {
+			if def.Tape[i], err = decodeJSONSymbol(raw, "", nil); err != nil {
+				return nil, fmt.Errorf("tape %d: %s", i, err.Error())
+			}
+		}
+	}
+
+	return &def, nil
+}
+
+// EncodeJSON writes the definition in the format read by DecodeJSON.
+//
+// States and operations are ordered by name and symbol, so the same
+// definition is always encoded the same way. The wildcard is "*", or a
+// longer sequence of '*' if the program has a "*" symbol.
+func EncodeJSON(w io.Writer, d *Definition) error {
+	states := d.states()
+	jd := jsonDefinition{
+		Start:  d.Start.Name,
+		States: make([]jsonState, len(states)),
+		Ops:    []jsonOp{},
+	}
+
+	names := make(map[string]bool, len(states))
+	for i, s := range states {
+		if names[s.Name] {
+			return fmt.Errorf("state %q is both halting and not halting", s.Name)
+		}
+		names[s.Name] = true
+		jd.States[i] = jsonState{Name: s.Name, Halt: s.Halt}
+	}
+
+	var ops []Op
+	if d.Program != nil {
+		ops = d.Program.SortedOps()
+	}
+	jd.Wildcard = chooseWildcard(ops, d.Tape)
+
+	for _, op := range ops {
+		if !validMovement(op.Movement) {
+			return fmt.Errorf("op %v %v: unknown movement %q", op.State, op.Symbol, op.Movement)
+		}
+		read, err := encodeJSONSymbol(op.Symbol, jd.Wildcard, ANY)
+		if err != nil {
+			return fmt.Errorf("op %v %v: read: %s", op.State, op.Symbol, err.Error())
+		}
+		write, err := encodeJSONSymbol(op.WriteSymbol, jd.Wildcard, KEEP)
+		if err != nil {
+			return fmt.Errorf("op %v %v: write: %s", op.State, op.Symbol, err.Error())
+		}
+		jd.Ops = append(jd.Ops, jsonOp{
+			State: op.State.Name,
+			Read:  read,
+			Write: write,
+			Move:  op.Movement,
+			Next:  op.NextState.Name,
+		})
+	}
+
+	if len(d.Tape) > 0 || d.TapeOffset != 0 || d.HeadPos != 0 {
+		jd.Tape = &jsonTape{Offset: d.TapeOffset, Head: d.HeadPos, Symbols: make([]json.RawMessage, len(d.Tape))}
+		for i, s := range d.Tape {
+			raw, err := encodeJSONSymbol(s, "", nil)
+			if err != nil {
+				return fmt.Errorf("tape %d: %s", i, err.Error())
+			}
+			jd.Tape.Symbols[i] = raw
+		}
+	}
+
+	if jd.Wildcard == defaultWildcard {
+		jd.Wildcard = ""
+	}
+
+	return writeJSONDefinition(w, jd)
+}
+
+// writeJSONDefinition writes the definition with one state or operation
+// per line.
+func writeJSONDefinition(w io.Writer, jd jsonDefinition) error {
+	buff := bytes.Buffer{}
+	buff.WriteString("{\n")
+
+	start, _ := json.Marshal(jd.Start)
+	fmt.Fprintf(&buff, "  \"start\": %s,\n", start)
+	if jd.Wildcard != "" {
+		wildcard, _ := json.Marshal(jd.Wildcard)
+		fmt.Fprintf(&buff, "  \"wildcard\": %s,\n", wildcard)
+	}
+
+	states := make([]interface{}, len(jd.States))
+	for i := range jd.States {
+		states[i] = jd.States[i]
+	}
+	ops := make([]interface{}, len(jd.Ops))
+	for i := range jd.Ops {
+		ops[i] = jd.Ops[i]
+	}
+	if err := writeJSONList(&buff, "states", states); err != nil {
+		return err
+	}
+	buff.WriteString(",\n")
+	if err := writeJSONList(&buff, "ops", ops); err != nil {
+		return err
+	}
+
+	if jd.Tape != nil {
+		tape, err := json.Marshal(jd.Tape)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buff, ",\n  \"tape\": %s", tape)
+	}
+	buff.WriteString("\n}\n")
+
+	_, err := buff.WriteTo(w)
+	return err
+}
+
+// writeJSONList writes a list field with one item per line.
+func writeJSONList(buff *bytes.Buffer, name string, items []interface{}) error {
+	fmt.Fprintf(buff, "  %q: [", name)
+	for i, item := range items {
+		line, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			buff.WriteString(",")
+		}
+		buff.WriteString("\n    ")
+		buff.Write(line)
+	}
+	if len(items) > 0 {
+		buff.WriteString("\n  ")
+	}
+	buff.WriteString("]")
+	return nil
+}
+
+// validMovement tells if the movement is LEFT, RIGHT or STAY.
+func validMovement(movement string) bool {
+	return movement == LEFT || movement == RIGHT || movement == STAY
+}
+
+// chooseWildcard returns the shortest sequence of '*' that is not a symbol
+// of the operations or the tape.
+func chooseWildcard(ops []Op, tape []Symbol) string {
+	used := make(map[Symbol]bool)
+	for _, op := range ops {
+		used[op.Symbol] = true
+		used[op.WriteSymbol] = true
+	}
+	for _, s := range tape {
+		used[s] = true
+	}
+
+	wildcard := defaultWildcard
+	for used[wildcard] {
+		wildcard += defaultWildcard
+	}
+	return wildcard
+}
+
+// decodeJSONSymbol decodes a symbol. The wildcard string is decoded as the
+// special symbol.
+func decodeJSONSymbol(raw json.RawMessage, wildcard string, special Symbol) (Symbol, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("missing symbol")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	switch s := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if wildcard != "" && s == wildcard {
+			return special, nil
+		}
+		return s, nil
+	case json.Number:
+		n, err := s.Int64()
+		if err != nil || int64(int(n)) != n {
+			return nil, fmt.Errorf("symbol %s is not an int", s)
+		}
+		return int(n), nil
+	default:
+		return nil, fmt.Errorf("unsupported symbol %s", strings.TrimSpace(string(raw)))
+	}
+}
+
+// encodeJSONSymbol encodes a symbol. The special symbol is encoded as the
+// wildcard string.
+func encodeJSONSymbol(s Symbol, wildcard string, special Symbol) (json.RawMessage, error) {
+	if special != nil && s == special {
+		return json.Marshal(wildcard)
+	}
+	switch v := s.(type) {
+	case nil, int, string:
+		return json.Marshal(v)
+	default:
+		return nil, fmt.Errorf("unsupported symbol type %T", s)
+	}
+}
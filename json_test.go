@@ -0,0 +1,143 @@
+package turing_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/massahud/turing/machines"
+	"github.com/stretchr/testify/assert"
+)
+
+// createSeparate01 returns the start state and the program of the machine
+// that separates 0's from 1's of the given sequence.
+func createSeparate01() (turing.State, *turing.Program) {
+	separate := machines.SeparateZeroOne()
+	return separate.Start, separate.Program
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("Decode", func(t *testing.T) {
+		t.Log("should decode a machine definition")
+
+		file, err := os.Open("testdata/separate01.json")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer file.Close()
+
+		def, err := turing.DecodeJSON(file)
+		if assert.NoError(t, err) {
+			start, program := createSeparate01()
+			assert.Equal(t, start, def.Start)
+			assert.ElementsMatch(t, program.ListOps(), def.Program.ListOps())
+			assert.Equal(t, []turing.Symbol{0, 1, 0, 1, 0, 1, 1, 1, 0, 1}, def.Tape)
+			assert.Equal(t, 0, def.TapeOffset)
+			assert.Equal(t, 0, def.HeadPos)
+
+			machine := def.NewMachine()
+			if assert.NoError(t, machine.Run()) {
+				assert.Equal(t, " 0: 0\n 1: 0\n 2: 0\n 3: 0\n[4: 1]\n 5: 1\n", machine.Head.PrintTape(0, 5))
+			}
+		}
+	})
+
+	t.Run("Encode", func(t *testing.T) {
+		t.Log("should encode a machine definition with a stable order")
+
+		expected, err := ioutil.ReadFile("testdata/separate01.json")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		start, program := createSeparate01()
+		def := turing.Definition{
+			Program: program,
+			Start:   start,
+			Tape:    []turing.Symbol{0, 1, 0, 1, 0, 1, 1, 1, 0, 1},
+		}
+
+		for i := 0; i < 5; i++ {
+			buff := bytes.Buffer{}
+			if assert.NoError(t, turing.EncodeJSON(&buff, &def)) {
+				assert.Equal(t, string(expected), buff.String())
+			}
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Log("should decode an encoded definition to the same definition")
+
+		state := turing.State{"state", false}
+		halt := turing.State{"halt", true}
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, "*", "**", turing.RIGHT, state})
+		program.AddOp(turing.Op{state, turing.ANY, turing.KEEP, turing.LEFT, state})
+		program.AddOp(turing.Op{state, nil, "a b", turing.STAY, halt})
+		program.AddOp(turing.Op{state, -7, 12, turing.STAY, halt})
+
+		def := turing.Definition{Program: &program, Start: state, Tape: []turing.Symbol{"*", nil, 3}, TapeOffset: -2, HeadPos: 1}
+
+		buff := bytes.Buffer{}
+		if assert.NoError(t, turing.EncodeJSON(&buff, &def)) {
+			assert.Contains(t, buff.String(), `"wildcard": "***"`)
+
+			decoded, err := turing.DecodeJSON(&buff)
+			if assert.NoError(t, err) {
+				assert.Equal(t, def.Start, decoded.Start)
+				assert.Equal(t, def.Tape, decoded.Tape)
+				assert.Equal(t, def.TapeOffset, decoded.TapeOffset)
+				assert.Equal(t, def.HeadPos, decoded.HeadPos)
+				assert.Equal(t, program.SortedOps(), decoded.Program.SortedOps())
+			}
+		}
+	})
+
+	t.Run("DecodeErrors", func(t *testing.T) {
+		t.Log("should not decode invalid definitions")
+
+		tests := []struct {
+			json string
+			err  string
+		}{
+			{`{"start": "a", "states": [{"name": "a"}, {"name": "a"}]}`, `duplicated state "a"`},
+			{`{"start": "b", "states": [{"name": "a"}]}`, `start: unknown state "b"`},
+			{`{"start": "a", "states": [{"name": "a"}], "ops": [{"state": "a", "read": 1, "write": 1, "move": "left", "next": "b"}]}`, `op 0: unknown state "b"`},
+			{`{"start": "a", "states": [{"name": "a"}], "ops": [{"state": "a", "read": 1, "write": 1, "move": "up", "next": "a"}]}`, `op 0: unknown movement "up"`},
+			{`{"start": "a", "states": [{"name": "a"}], "ops": [{"state": "a", "read": 1.5, "write": 1, "move": "left", "next": "a"}]}`, `op 0: read: symbol 1.5 is not an int`},
+			{`{"start": "a", "states": [{"name": "a"}], "ops": [{"state": "a", "read": 1, "move": "left", "next": "a"}]}`, `op 0: write: missing symbol`},
+			{`{"start": "a", "states": [{"name": "a"}], "tape": {"symbols": [true]}}`, `tape 0: unsupported symbol true`},
+			{`{"start": "a", "state": []}`, `invalid JSON definition: json: unknown field "state"`},
+		}
+		for _, tt := range tests {
+			_, err := turing.DecodeJSON(strings.NewReader(tt.json))
+			assert.EqualError(t, err, tt.err, tt.json)
+		}
+	})
+
+	t.Run("EncodeErrors", func(t *testing.T) {
+		t.Log("should not encode definitions that can't be decoded")
+
+		state := turing.State{"state", false}
+		halt := turing.State{"state", true}
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, 1, byte(1), turing.RIGHT, state})
+		err := turing.EncodeJSON(&bytes.Buffer{}, &turing.Definition{Program: &program, Start: state})
+		assert.EqualError(t, err, "op state 1: write: unsupported symbol type uint8")
+
+		program = turing.Program{}
+		program.AddOp(turing.Op{state, 1, 1, "up", state})
+		err = turing.EncodeJSON(&bytes.Buffer{}, &turing.Definition{Program: &program, Start: state})
+		assert.EqualError(t, err, `op state 1: unknown movement "up"`)
+
+		program = turing.Program{}
+		program.AddOp(turing.Op{state, 1, 1, turing.STAY, halt})
+		err = turing.EncodeJSON(&bytes.Buffer{}, &turing.Definition{Program: &program, Start: state})
+		assert.EqualError(t, err, `state "state" is both halting and not halting`)
+	})
+}
@@ -0,0 +1,300 @@
+package turing
+
+import "fmt"
+
+// Cycle is a repetition found by a LoopDetector. A machine on a cycle never
+// halts.
+type Cycle struct {
+	// Start is the step of the configuration that repeats. The cycle may have
+	// started before it.
+	Start int
+	// Period is the number of steps between the repetitions.
+	Period int
+	// Translation is how many positions the head drifts on each period, 0
+	// when the configuration repeats exactly.
+	Translation int
+}
+
+// String describes the cycle.
+func (c Cycle) String() string {
+	if c.Translation == 0 {
+		return fmt.Sprintf("configuration of step %d repeats every %d steps", c.Start, c.Period)
+	}
+	return fmt.Sprintf("configuration of step %d repeats every %d steps, moved %d positions", c.Start, c.Period, c.Translation)
+}
+
+// LoopDetector finds machines that will never halt because they repeat a
+// configuration. Set it on Machine.LoopDetector to stop RunContext with the
+// Looping reason when a cycle is found.
+//
+// It finds exact cycles, where the state, the head position and the tape
+// repeat, and translated cycles, where the machine repeats the same pattern
+// moving over the blank tape. Translated cycles are only found on tapes
+// created by this package.
+//
+// The configurations are compared to references taken on doubling intervals,
+// so a cycle is found after at most about twice its start and period steps.
+// A detector must be used with a single machine, and be reset when the
+// machine configuration is changed by anything other than its steps.
+type LoopDetector struct {
+	observed int
+	nextRef  int
+
+	// exact cycle reference
+	refStep  int
+	refState State
+	refPos   int
+	refCells map[int]Symbol
+	diff     map[int]bool
+
+	// translated cycle references, for each direction
+	right translationRef
+	left  translationRef
+
+	// non blank cells extent, tracked from the steps after it is read from
+	// the tape. A stale end was erased, and is moved inwards when needed.
+	extentKnown bool
+	nonBlank    bool
+	minNonBlank int
+	maxNonBlank int
+	minStale    bool
+	maxStale    bool
+
+	found *Cycle
+}
+
+// translationRef is the configuration on a step where the head reached a new
+// position, used to find translated cycles.
+type translationRef struct {
+	valid bool
+	step  int
+	state State
+	pos   int
+	// from is the position of the first cell
+	from  int
+	cells []Symbol
+	// reach is the farthest position from pos the head went back to after
+	// the reference step
+	reach int
+}
+
+// NewLoopDetector creates a loop detector.
+func NewLoopDetector() *LoopDetector {
+	d := &LoopDetector{}
+	d.Reset()
+	return d
+}
+
+// Reset forgets the configurations seen.
+func (d *LoopDetector) Reset() {
+	*d = LoopDetector{nextRef: 1, refCells: make(map[int]Symbol), diff: make(map[int]bool)}
+}
+
+// observe checks the configuration of the machine after the step.
+func (d *LoopDetector) observe(m *Machine, e StepEvent) {
+	if d.refCells == nil {
+		d.Reset()
+	}
+	d.observed++
+	d.trackExtent(e)
+
+	if d.observed > 1 {
+		if _, touched := d.refCells[e.From]; !touched {
+			d.refCells[e.From] = e.Read
+		}
+		if e.Written == d.refCells[e.From] {
+			delete(d.diff, e.From)
+		} else {
+			d.diff[e.From] = true
+		}
+		if m.State == d.refState && e.To == d.refPos && len(d.diff) == 0 {
+			d.found = &Cycle{Start: d.refStep, Period: e.Step - d.refStep}
+			return
+		}
+	}
+
+	if d.right.valid && e.To < d.right.reach {
+		d.right.reach = e.To
+	}
+	if d.left.valid && e.To > d.left.reach {
+		d.left.reach = e.To
+	}
+	if e.To > e.From && e.To == m.Head.maxPos && m.Head.maxPos > m.Head.minPos {
+		d.checkTranslation(m, e, &d.right, 1)
+	}
+	if e.To < e.From && e.To == m.Head.minPos && m.Head.maxPos > m.Head.minPos {
+		d.checkTranslation(m, e, &d.left, -1)
+	}
+	if d.found != nil {
+		return
+	}
+
+	if d.observed == d.nextRef {
+		d.nextRef *= 2
+		d.refStep = e.Step
+		d.refState = m.State
+		d.refPos = e.To
+		d.refCells = make(map[int]Symbol)
+		d.diff = make(map[int]bool)
+	}
+}
+
+// checkTranslation compares the configuration on a step where the head
+// reached a new position in the direction with the reference, or takes a
+// new reference.
+func (d *LoopDetector) checkTranslation(m *Machine, e StepEvent, ref *translationRef, direction int) {
+	if ref.valid && m.State == ref.state && ref.reach*direction >= ref.from*direction {
+		shift := e.To - ref.pos
+		matches := true
+		for pos := ref.reach; pos*direction <= ref.pos*direction; pos += direction {
+			symbol, err := m.Head.tape.Get(pos + shift)
+			if err != nil || symbol != ref.cells[(pos-ref.from)*direction] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			d.found = &Cycle{Start: ref.step, Period: e.Step - ref.step, Translation: shift}
+			return
+		}
+	}
+
+	if ref.valid && e.Step < 2*ref.step {
+		return
+	}
+	tape, ok := m.Head.tape.(extentTape)
+	if !ok {
+		return
+	}
+	ref.valid = false
+	if min, max, nonBlank := d.extent(m, tape); nonBlank && (direction > 0 && max > e.To || direction < 0 && min < e.To) {
+		return
+	}
+
+	from := m.Head.minPos
+	if direction < 0 {
+		from = m.Head.maxPos
+	}
+	*ref = translationRef{valid: true, step: e.Step, state: m.State, pos: e.To, from: from, reach: e.To}
+	for pos := from; pos*direction <= e.To*direction; pos += direction {
+		symbol, err := m.Head.tape.Get(pos)
+		if err != nil {
+			ref.valid = false
+			return
+		}
+		ref.cells = append(ref.cells, symbol)
+	}
+}
+
+// trackExtent updates the non blank cells extent with the symbol written on
+// the step.
+func (d *LoopDetector) trackExtent(e StepEvent) {
+	switch {
+	case !d.extentKnown:
+	case e.Written != nil && !d.nonBlank:
+		d.nonBlank = true
+		d.minNonBlank, d.maxNonBlank = e.From, e.From
+		d.minStale, d.maxStale = false, false
+	case e.Written != nil:
+		if e.From <= d.minNonBlank {
+			d.minNonBlank, d.minStale = e.From, false
+		}
+		if e.From >= d.maxNonBlank {
+			d.maxNonBlank, d.maxStale = e.From, false
+		}
+	case d.nonBlank:
+		d.minStale = d.minStale || e.From == d.minNonBlank
+		d.maxStale = d.maxStale || e.From == d.maxNonBlank
+	}
+}
+
+// extent returns the non blank cells extent of the tape. It is read from the
+// tape only the first time, after that the erased ends are moved inwards
+// over the blank cells, so a run reads each cell about once.
+func (d *LoopDetector) extent(m *Machine, tape extentTape) (min, max int, nonBlank bool) {
+	if !d.extentKnown {
+		d.minNonBlank, d.maxNonBlank, d.nonBlank = tape.extent()
+		d.minStale, d.maxStale = false, false
+		d.extentKnown = true
+	}
+	for d.nonBlank && d.minStale {
+		if d.minNonBlank > d.maxNonBlank {
+			d.nonBlank, d.minStale, d.maxStale = false, false, false
+			break
+		}
+		symbol, err := m.Head.tape.Get(d.minNonBlank)
+		if err != nil {
+			d.extentKnown = false
+			return tape.extent()
+		}
+		if symbol != nil {
+			d.minStale = false
+			break
+		}
+		d.minNonBlank++
+	}
+	for d.nonBlank && d.maxStale {
+		symbol, err := m.Head.tape.Get(d.maxNonBlank)
+		if err != nil {
+			d.extentKnown = false
+			return tape.extent()
+		}
+		if symbol != nil {
+			d.maxStale = false
+			break
+		}
+		d.maxNonBlank--
+	}
+	return d.minNonBlank, d.maxNonBlank, d.nonBlank
+}
+
+// take returns the cycle found, and forgets it.
+func (d *LoopDetector) take() *Cycle {
+	found := d.found
+	d.found = nil
+	return found
+}
+
+// extentTape is a tape that knows where its non blank cells are.
+type extentTape interface {
+	// extent returns the smallest and biggest positions with non blank
+	// symbols, nonBlank is false when all cells are blank.
+	extent() (min, max int, nonBlank bool)
+}
+
+func (t *infiniteTape) extent() (min, max int, nonBlank bool) {
+	first, ok := t.firstNonBlank()
+	if !ok {
+		return 0, 0, false
+	}
+	last, _ := t.lastNonBlank()
+	return first, last, true
+}
+
+func (t *infiniteTape) firstNonBlank() (int, bool) {
+	for i := len(t.left) - 1; i >= 0; i-- {
+		if t.left[i] != nil {
+			return -i - 1, true
+		}
+	}
+	for i, s := range t.right {
+		if s != nil {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (t *infiniteTape) lastNonBlank() (int, bool) {
+	for i := len(t.right) - 1; i >= 0; i-- {
+		if t.right[i] != nil {
+			return i, true
+		}
+	}
+	for i, s := range t.left {
+		if s != nil {
+			return -i - 1, true
+		}
+	}
+	return 0, false
+}
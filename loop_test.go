@@ -0,0 +1,243 @@
+package turing_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoopDetector(t *testing.T) {
+	a := turing.State{"a", false}
+	b := turing.State{"b", false}
+	c := turing.State{"c", false}
+	halt := turing.State{"halt", true}
+
+	createMachine := func(tape turing.Tape, ops ...turing.Op) *turing.Machine {
+		program := turing.Program{}
+		for _, op := range ops {
+			program.AddOp(op)
+		}
+		if tape == nil {
+			tape = turing.NewInfiniteTape()
+		}
+		head := turing.Head{}
+		head.Attach(tape, 0)
+		return &turing.Machine{Head: &head, Program: &program, State: a, LoopDetector: turing.NewLoopDetector()}
+	}
+
+	t.Run("Cycles", func(t *testing.T) {
+		t.Log("should stop machines that repeat a configuration")
+
+		tests := []struct {
+			name  string
+			ops   []turing.Op
+			cycle turing.Cycle
+		}{
+			{
+				name: "Bounce",
+				ops: []turing.Op{
+					{a, turing.ANY, turing.KEEP, turing.RIGHT, b},
+					{b, turing.ANY, turing.KEEP, turing.LEFT, a},
+				},
+				cycle: turing.Cycle{Start: 1, Period: 2},
+			},
+			{
+				name: "WriteAndErase",
+				ops: []turing.Op{
+					{a, nil, 1, turing.RIGHT, b},
+					{b, nil, nil, turing.LEFT, c},
+					{c, 1, nil, turing.STAY, a},
+				},
+				cycle: turing.Cycle{Start: 1, Period: 3},
+			},
+			{
+				name:  "Right",
+				ops:   []turing.Op{{a, turing.ANY, 1, turing.RIGHT, a}},
+				cycle: turing.Cycle{Start: 1, Period: 1, Translation: 1},
+			},
+			{
+				name:  "Left",
+				ops:   []turing.Op{{a, turing.ANY, 1, turing.LEFT, a}},
+				cycle: turing.Cycle{Start: 1, Period: 1, Translation: -1},
+			},
+			{
+				name: "RightStepBack",
+				ops: []turing.Op{
+					{a, nil, 1, turing.LEFT, b},
+					{b, turing.ANY, turing.KEEP, turing.RIGHT, c},
+					{c, 1, turing.KEEP, turing.RIGHT, a},
+				},
+				cycle: turing.Cycle{Start: 2, Period: 3, Translation: 1},
+			},
+		}
+		for _, tt := range tests {
+			machine := createMachine(nil, tt.ops...)
+			result, err := machine.RunContext(context.Background(), 1000)
+			if assert.NoError(t, err, tt.name) {
+				assert.Equal(t, turing.Looping, result.Reason, tt.name)
+				assert.Equal(t, tt.cycle, result.Cycle, tt.name)
+			}
+		}
+	})
+
+	t.Run("Counter", func(t *testing.T) {
+		t.Log("should not stop machines that never halt without repeating")
+
+		inc := turing.State{"a", false}
+		ret := turing.State{"b", false}
+		machine := createMachine(nil,
+			turing.Op{inc, 1, 0, turing.LEFT, inc},
+			turing.Op{inc, 0, 1, turing.RIGHT, ret},
+			turing.Op{inc, nil, 1, turing.RIGHT, ret},
+			turing.Op{ret, turing.ANY, turing.KEEP, turing.RIGHT, ret},
+			turing.Op{ret, nil, nil, turing.LEFT, inc},
+		)
+		result, err := machine.RunContext(context.Background(), 100000)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.BudgetExhausted, result.Reason)
+		}
+	})
+
+	t.Run("BusyBeaver", func(t *testing.T) {
+		t.Log("should not stop the 4 states busy beaver, that halts after 107 steps")
+
+		d := turing.State{"d", false}
+		machine := createMachine(nil,
+			turing.Op{a, nil, 1, turing.RIGHT, b},
+			turing.Op{a, 1, 1, turing.LEFT, b},
+			turing.Op{b, nil, 1, turing.LEFT, a},
+			turing.Op{b, 1, nil, turing.LEFT, c},
+			turing.Op{c, nil, 1, turing.RIGHT, halt},
+			turing.Op{c, 1, 1, turing.LEFT, d},
+			turing.Op{d, nil, 1, turing.RIGHT, d},
+			turing.Op{d, 1, nil, turing.RIGHT, a},
+		)
+		result, err := machine.RunContext(context.Background(), 1000)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.RunResult{Steps: 107, Reason: turing.Halted}, result)
+		}
+	})
+
+	t.Run("NonBlankTape", func(t *testing.T) {
+		t.Log("should not find translated cycles before the end of the tape")
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 1, 1, 1, 1, 1, 1, 1, 1)
+		machine := createMachine(tape,
+			turing.Op{a, 1, 1, turing.RIGHT, a},
+			turing.Op{a, nil, nil, turing.STAY, halt},
+		)
+		result, err := machine.RunContext(context.Background(), 1000)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.Halted, result.Reason)
+		}
+
+		tape = turing.NewInfiniteTape()
+		tape.Set(0, 1, 1, 1, 1, 1, 1, 1, 1)
+		machine = createMachine(tape, turing.Op{a, turing.ANY, turing.KEEP, turing.RIGHT, a})
+		result, err = machine.RunContext(context.Background(), 1000)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.Looping, result.Reason)
+			assert.Equal(t, 1, result.Cycle.Translation)
+			assert.True(t, result.Cycle.Start >= 8, "cycle starts after the tape, got %v", result.Cycle)
+		}
+	})
+
+	t.Run("OtherTape", func(t *testing.T) {
+		t.Log("should only find exact cycles on tapes from other packages")
+
+		machine := createMachine(mapTape{}, turing.Op{a, turing.ANY, 1, turing.RIGHT, a})
+		result, err := machine.RunContext(context.Background(), 1000)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.BudgetExhausted, result.Reason)
+		}
+
+		machine = createMachine(mapTape{},
+			turing.Op{a, turing.ANY, turing.KEEP, turing.RIGHT, b},
+			turing.Op{b, turing.ANY, turing.KEEP, turing.LEFT, a},
+		)
+		result, err = machine.RunContext(context.Background(), 1000)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.Looping, result.Reason)
+		}
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		t.Log("should stop Run with an error when the machine is looping")
+
+		machine := createMachine(nil, turing.Op{a, turing.ANY, 1, turing.RIGHT, a})
+		err := machine.Run()
+		assert.EqualError(t, err, "Error at instruction 2: machine is looping, configuration of step 1 repeats every 1 steps, moved 1 positions")
+		assert.True(t, errors.Is(err, turing.ErrLooping))
+		var loopErr *turing.LoopError
+		if assert.True(t, errors.As(err, &loopErr)) {
+			assert.Equal(t, turing.Cycle{Start: 1, Period: 1, Translation: 1}, loopErr.Cycle)
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Log("should find the cycle again when the run is resumed")
+
+		machine := createMachine(nil,
+			turing.Op{a, turing.ANY, turing.KEEP, turing.RIGHT, b},
+			turing.Op{b, turing.ANY, turing.KEEP, turing.LEFT, a},
+		)
+		first, err := machine.RunContext(context.Background(), 0)
+		if !assert.NoError(t, err) {
+			return
+		}
+		second, err := machine.RunContext(context.Background(), 0)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.Looping, second.Reason)
+			assert.Equal(t, 2, second.Cycle.Period)
+			assert.True(t, second.Cycle.Start > first.Cycle.Start)
+		}
+	})
+}
+
+func BenchmarkLoopDetector(b *testing.B) {
+	// the machines sweep right over the input, keeping or erasing it
+	sweeps := []struct {
+		name  string
+		write turing.Symbol
+	}{
+		{"Keep", turing.KEEP},
+		{"Erase", nil},
+	}
+	state := turing.State{"right", false}
+	halt := turing.State{"halt", true}
+
+	for _, tt := range tapes {
+		for _, sweep := range sweeps {
+			program := turing.Program{}
+			program.AddOp(turing.Op{State: state, Symbol: 1, WriteSymbol: sweep.write, Movement: turing.RIGHT, NextState: state})
+			program.AddOp(turing.Op{State: state, Symbol: nil, WriteSymbol: turing.KEEP, Movement: turing.STAY, NextState: halt})
+
+			for _, cells := range []int{1000, 10000, 100000} {
+				input := make([]turing.Symbol, cells)
+				for i := range input {
+					input[i] = 1
+				}
+				b.Run(fmt.Sprintf("%s/%s/%d", tt.name, sweep.name, cells), func(b *testing.B) {
+					var elapsed time.Duration
+					for i := 0; i < b.N; i++ {
+						tape := tt.new()
+						tape.Set(0, input...)
+						head := turing.Head{}
+						head.Attach(tape, 0)
+						machine := turing.Machine{Head: &head, Program: &program, State: state, LoopDetector: turing.NewLoopDetector()}
+						start := time.Now()
+						machine.RunContext(context.Background(), 0)
+						elapsed += time.Since(start)
+					}
+					b.ReportMetric(float64(elapsed.Nanoseconds())/float64(b.N*cells), "ns/step")
+				})
+			}
+		}
+	}
+}
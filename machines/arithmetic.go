@@ -0,0 +1,270 @@
+package machines
+
+import (
+	"fmt"
+
+	"github.com/massahud/turing"
+)
+
+// BinaryIncrement adds one to a binary number, and halts on its first digit.
+func BinaryIncrement() *Machine {
+	right := turing.State{Name: "right"}
+	carry := turing.State{Name: "carry"}
+	left := turing.State{Name: "left"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	return &Machine{
+		Name: "binary increment",
+		Program: newProgram(
+			// goes to the last digit
+			op(right, 0, turing.KEEP, turing.RIGHT, right),
+			op(right, 1, turing.KEEP, turing.RIGHT, right),
+			op(right, nil, turing.KEEP, turing.LEFT, carry),
+			// adds one, carrying it over the ones
+			op(carry, 1, 0, turing.LEFT, carry),
+			op(carry, 0, 1, turing.LEFT, left),
+			op(carry, nil, 1, turing.STAY, halt),
+			// returns to the first digit
+			op(left, turing.ANY, turing.KEEP, turing.LEFT, left),
+			op(left, nil, turing.KEEP, turing.RIGHT, halt),
+		),
+		Start:  right,
+		Encode: turing.Binary.Encode,
+		Decode: decode(turing.Binary),
+	}
+}
+
+// BinaryDecrement subtracts one from a positive binary number, erases the
+// leading zeros of the result and halts on its first digit.
+func BinaryDecrement() *Machine {
+	right := turing.State{Name: "right"}
+	borrow := turing.State{Name: "borrow"}
+	left := turing.State{Name: "left"}
+	trim := turing.State{Name: "trim"}
+	peek := turing.State{Name: "peek"}
+	erase := turing.State{Name: "erase"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	return &Machine{
+		Name: "binary decrement",
+		Program: newProgram(
+			// goes to the last digit
+			op(right, 0, turing.KEEP, turing.RIGHT, right),
+			op(right, 1, turing.KEEP, turing.RIGHT, right),
+			op(right, nil, turing.KEEP, turing.LEFT, borrow),
+			// subtracts one, borrowing it from the first one on the left
+			op(borrow, 0, 1, turing.LEFT, borrow),
+			op(borrow, 1, 0, turing.LEFT, left),
+			// returns to the first digit
+			op(left, turing.ANY, turing.KEEP, turing.LEFT, left),
+			op(left, nil, turing.KEEP, turing.RIGHT, trim),
+			// erases the leading zeros, keeping the last digit
+			op(trim, 1, turing.KEEP, turing.STAY, halt),
+			op(trim, 0, turing.KEEP, turing.RIGHT, peek),
+			op(peek, nil, turing.KEEP, turing.LEFT, halt),
+			op(peek, turing.ANY, turing.KEEP, turing.LEFT, erase),
+			op(erase, 0, nil, turing.RIGHT, trim),
+		),
+		Start: right,
+		Encode: func(input interface{}) ([]turing.Symbol, error) {
+			if input == 0 {
+				return nil, fmt.Errorf("input %v is not a positive number", input)
+			}
+			return turing.Binary.Encode(input)
+		},
+		Decode: decode(turing.Binary),
+	}
+}
+
+// UnaryAddition adds two unary numbers separated by a 0, and halts on the
+// first digit of the sum.
+func UnaryAddition() *Machine {
+	scan := turing.State{Name: "scan"}
+	end := turing.State{Name: "end"}
+	erase := turing.State{Name: "erase"}
+	left := turing.State{Name: "left"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	return &Machine{
+		Name: "unary addition",
+		Program: newProgram(
+			// joins the numbers, replacing the separator by a one
+			op(scan, 1, turing.KEEP, turing.RIGHT, scan),
+			op(scan, 0, 1, turing.RIGHT, end),
+			// erases the last one
+			op(end, 1, turing.KEEP, turing.RIGHT, end),
+			op(end, nil, turing.KEEP, turing.LEFT, erase),
+			op(erase, 1, nil, turing.LEFT, left),
+			// returns to the first digit
+			op(left, 1, turing.KEEP, turing.LEFT, left),
+			op(left, nil, turing.KEEP, turing.RIGHT, halt),
+		),
+		Start: scan,
+		Encode: func(input interface{}) ([]turing.Symbol, error) {
+			a, b, err := pair(input)
+			if err != nil {
+				return nil, err
+			}
+			return append(append(unary(a), 0), unary(b)...), nil
+		},
+		Decode: decode(turing.Unary),
+	}
+}
+
+// BinaryAddition adds two binary numbers separated by a +, decrementing the
+// second and incrementing the first until the second is zero. It halts on the
+// first digit of the sum.
+func BinaryAddition() *Machine {
+	right := turing.State{Name: "right"}
+	decrement := turing.State{Name: "decrement"}
+	first := turing.State{Name: "first"}
+	increment := turing.State{Name: "increment"}
+	erase := turing.State{Name: "erase"}
+	skip := turing.State{Name: "skip"}
+	left := turing.State{Name: "left"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	return &Machine{
+		Name: "binary addition",
+		Program: newProgram(
+			// goes to the last digit of the second number
+			op(right, turing.ANY, turing.KEEP, turing.RIGHT, right),
+			op(right, nil, turing.KEEP, turing.LEFT, decrement),
+			// decrements the second number
+			op(decrement, 0, 1, turing.LEFT, decrement),
+			op(decrement, 1, 0, turing.LEFT, first),
+			op(decrement, "+", nil, turing.RIGHT, erase),
+			// increments the first number
+			op(first, turing.ANY, turing.KEEP, turing.LEFT, first),
+			op(first, "+", turing.KEEP, turing.LEFT, increment),
+			op(increment, 1, 0, turing.LEFT, increment),
+			op(increment, 0, 1, turing.RIGHT, right),
+			op(increment, nil, 1, turing.RIGHT, right),
+			// the second number was zero, and the decrement turned it into
+			// ones: erases it and returns to the first digit of the sum
+			op(erase, 1, nil, turing.RIGHT, erase),
+			op(erase, nil, turing.KEEP, turing.LEFT, skip),
+			op(skip, nil, turing.KEEP, turing.LEFT, skip),
+			op(skip, turing.ANY, turing.KEEP, turing.LEFT, left),
+			op(left, turing.ANY, turing.KEEP, turing.LEFT, left),
+			op(left, nil, turing.KEEP, turing.RIGHT, halt),
+		),
+		Start: right,
+		Encode: func(input interface{}) ([]turing.Symbol, error) {
+			a, b, err := pair(input)
+			if err != nil {
+				return nil, err
+			}
+			return append(append(binary(a), "+"), binary(b)...), nil
+		},
+		Decode: decode(turing.Binary),
+	}
+}
+
+// UnaryMultiplication multiplies two unary numbers separated by a 0. For each
+// digit of the first number, it appends the second number after another 0,
+// marking the digits it already used with x and y. Then it erases the numbers
+// and halts on the first digit of the product.
+func UnaryMultiplication() *Machine {
+	start := turing.State{Name: "start"}
+	rewind := turing.State{Name: "rewind"}
+	nextA := turing.State{Name: "nextA"}
+	toB := turing.State{Name: "toB"}
+	nextB := turing.State{Name: "nextB"}
+	toProduct := turing.State{Name: "toProduct"}
+	append1 := turing.State{Name: "append"}
+	backProduct := turing.State{Name: "backProduct"}
+	backB := turing.State{Name: "backB"}
+	restoreB := turing.State{Name: "restoreB"}
+	backA := turing.State{Name: "backA"}
+	eraseA := turing.State{Name: "eraseA"}
+	skip := turing.State{Name: "skip"}
+	eraseB := turing.State{Name: "eraseB"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	return &Machine{
+		Name: "unary multiplication",
+		Program: newProgram(
+			// writes the separator of the product
+			op(start, turing.ANY, turing.KEEP, turing.RIGHT, start),
+			op(start, nil, 0, turing.LEFT, rewind),
+			op(rewind, turing.ANY, turing.KEEP, turing.LEFT, rewind),
+			op(rewind, nil, turing.KEEP, turing.RIGHT, nextA),
+			// marks the next digit of the first number
+			op(nextA, 1, "x", turing.RIGHT, toB),
+			op(nextA, 0, turing.KEEP, turing.LEFT, eraseA),
+			op(toB, 1, turing.KEEP, turing.RIGHT, toB),
+			op(toB, 0, turing.KEEP, turing.RIGHT, nextB),
+			// marks the next digit of the second number and appends a one
+			// to the product
+			op(nextB, 1, "y", turing.RIGHT, toProduct),
+			op(nextB, 0, turing.KEEP, turing.LEFT, restoreB),
+			op(toProduct, turing.ANY, turing.KEEP, turing.RIGHT, toProduct),
+			op(toProduct, 0, turing.KEEP, turing.RIGHT, append1),
+			op(append1, 1, turing.KEEP, turing.RIGHT, append1),
+			op(append1, nil, 1, turing.LEFT, backProduct),
+			op(backProduct, 1, turing.KEEP, turing.LEFT, backProduct),
+			op(backProduct, 0, turing.KEEP, turing.LEFT, backB),
+			op(backB, 1, turing.KEEP, turing.LEFT, backB),
+			op(backB, "y", turing.KEEP, turing.RIGHT, nextB),
+			// unmarks the second number and returns to the first one
+			op(restoreB, "y", 1, turing.LEFT, restoreB),
+			op(restoreB, 0, turing.KEEP, turing.LEFT, backA),
+			op(backA, 1, turing.KEEP, turing.LEFT, backA),
+			op(backA, "x", turing.KEEP, turing.RIGHT, nextA),
+			// erases the numbers and the separators
+			op(eraseA, "x", nil, turing.LEFT, eraseA),
+			op(eraseA, nil, turing.KEEP, turing.RIGHT, skip),
+			op(skip, nil, turing.KEEP, turing.RIGHT, skip),
+			op(skip, 0, nil, turing.RIGHT, eraseB),
+			op(eraseB, 1, nil, turing.RIGHT, eraseB),
+			op(eraseB, 0, nil, turing.RIGHT, halt),
+		),
+		Start: start,
+		Encode: func(input interface{}) ([]turing.Symbol, error) {
+			a, b, err := pair(input)
+			if err != nil {
+				return nil, err
+			}
+			return append(append(unary(a), 0), unary(b)...), nil
+		},
+		Decode: decode(turing.Unary),
+	}
+}
+
+// BinaryToUnary converts a binary number to unary, decrementing it and
+// appending a one after a # until it is zero. Then it erases the binary
+// number and halts on the first digit of the result.
+func BinaryToUnary() *Machine {
+	start := turing.State{Name: "start"}
+	decrement := turing.State{Name: "decrement"}
+	add := turing.State{Name: "add"}
+	back := turing.State{Name: "back"}
+	erase := turing.State{Name: "erase"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	return &Machine{
+		Name: "binary to unary",
+		Program: newProgram(
+			// writes the separator after the binary number
+			op(start, turing.ANY, turing.KEEP, turing.RIGHT, start),
+			op(start, nil, "#", turing.LEFT, decrement),
+			// decrements the binary number
+			op(decrement, 0, 1, turing.LEFT, decrement),
+			op(decrement, 1, 0, turing.RIGHT, add),
+			op(decrement, nil, turing.KEEP, turing.RIGHT, erase),
+			// appends a one to the unary number
+			op(add, turing.ANY, turing.KEEP, turing.RIGHT, add),
+			op(add, nil, 1, turing.LEFT, back),
+			op(back, turing.ANY, turing.KEEP, turing.LEFT, back),
+			op(back, "#", turing.KEEP, turing.LEFT, decrement),
+			// the binary number was zero, and the decrement turned it into
+			// ones: erases it with the separator
+			op(erase, 1, nil, turing.RIGHT, erase),
+			op(erase, "#", nil, turing.RIGHT, halt),
+		),
+		Start:  start,
+		Encode: turing.Binary.Encode,
+		Decode: decode(turing.Unary),
+	}
+}
@@ -0,0 +1,122 @@
+package machines
+
+import (
+	"github.com/massahud/turing"
+)
+
+// Palindrome recognizes the words of 0s and 1s that are palindromes. It
+// erases the first symbol of the word and compares it with the last one,
+// until the word is empty.
+func Palindrome() *Machine {
+	start := turing.State{Name: "start"}
+	have0 := turing.State{Name: "have0"}
+	have1 := turing.State{Name: "have1"}
+	check0 := turing.State{Name: "check0"}
+	check1 := turing.State{Name: "check1"}
+	back := turing.State{Name: "back"}
+
+	return &Machine{
+		Name: "palindrome",
+		Program: newProgram(
+			// erases the first symbol
+			op(start, 0, nil, turing.RIGHT, have0),
+			op(start, 1, nil, turing.RIGHT, have1),
+			op(start, nil, turing.KEEP, turing.STAY, turing.AcceptState),
+			// goes to the last symbol
+			op(have0, turing.ANY, turing.KEEP, turing.RIGHT, have0),
+			op(have0, nil, turing.KEEP, turing.LEFT, check0),
+			op(have1, turing.ANY, turing.KEEP, turing.RIGHT, have1),
+			op(have1, nil, turing.KEEP, turing.LEFT, check1),
+			// erases the last symbol if it is the same as the first
+			op(check0, 0, nil, turing.LEFT, back),
+			op(check0, 1, turing.KEEP, turing.STAY, turing.RejectState),
+			op(check0, nil, turing.KEEP, turing.STAY, turing.AcceptState),
+			op(check1, 1, nil, turing.LEFT, back),
+			op(check1, 0, turing.KEEP, turing.STAY, turing.RejectState),
+			op(check1, nil, turing.KEEP, turing.STAY, turing.AcceptState),
+			// returns to the first symbol
+			op(back, turing.ANY, turing.KEEP, turing.LEFT, back),
+			op(back, nil, turing.KEEP, turing.RIGHT, start),
+		),
+		Start:  start,
+		Encode: encodeWord("01"),
+		Decode: decodeAccepted,
+	}
+}
+
+// BalancedParentheses recognizes the words of balanced parentheses. It marks
+// the first ) with an x, and the ( that matches it, until there are no )
+// left, then it checks that there are no ( left.
+func BalancedParentheses() *Machine {
+	scan := turing.State{Name: "scan"}
+	match := turing.State{Name: "match"}
+	check := turing.State{Name: "check"}
+
+	return &Machine{
+		Name: "balanced parentheses",
+		Program: newProgram(
+			// finds the first )
+			op(scan, "(", turing.KEEP, turing.RIGHT, scan),
+			op(scan, "x", turing.KEEP, turing.RIGHT, scan),
+			op(scan, ")", "x", turing.LEFT, match),
+			op(scan, nil, turing.KEEP, turing.LEFT, check),
+			// finds the ( that matches it
+			op(match, "x", turing.KEEP, turing.LEFT, match),
+			op(match, "(", "x", turing.RIGHT, scan),
+			op(match, nil, turing.KEEP, turing.STAY, turing.RejectState),
+			// checks that all ( were matched
+			op(check, "x", turing.KEEP, turing.LEFT, check),
+			op(check, "(", turing.KEEP, turing.STAY, turing.RejectState),
+			op(check, nil, turing.KEEP, turing.STAY, turing.AcceptState),
+		),
+		Start:  scan,
+		Encode: encodeWord("()"),
+		Decode: decodeAccepted,
+	}
+}
+
+// ABC recognizes the words a^n b^n c^n. It marks the first a with an x, the
+// first b with a y and the first c with a z, until there are no a left, then
+// it checks that only ys and zs are left.
+func ABC() *Machine {
+	start := turing.State{Name: "start"}
+	findB := turing.State{Name: "findB"}
+	findC := turing.State{Name: "findC"}
+	back := turing.State{Name: "back"}
+	checkY := turing.State{Name: "checkY"}
+	checkZ := turing.State{Name: "checkZ"}
+
+	return &Machine{
+		Name: "a^n b^n c^n",
+		Program: newProgram(
+			// marks the first a
+			op(start, "a", "x", turing.RIGHT, findB),
+			op(start, "y", turing.KEEP, turing.RIGHT, checkY),
+			op(start, nil, turing.KEEP, turing.STAY, turing.AcceptState),
+			op(start, turing.ANY, turing.KEEP, turing.STAY, turing.RejectState),
+			// marks the first b
+			op(findB, "a", turing.KEEP, turing.RIGHT, findB),
+			op(findB, "y", turing.KEEP, turing.RIGHT, findB),
+			op(findB, "b", "y", turing.RIGHT, findC),
+			op(findB, turing.ANY, turing.KEEP, turing.STAY, turing.RejectState),
+			// marks the first c
+			op(findC, "b", turing.KEEP, turing.RIGHT, findC),
+			op(findC, "z", turing.KEEP, turing.RIGHT, findC),
+			op(findC, "c", "z", turing.LEFT, back),
+			op(findC, turing.ANY, turing.KEEP, turing.STAY, turing.RejectState),
+			// returns to the last x
+			op(back, "x", turing.KEEP, turing.RIGHT, start),
+			op(back, turing.ANY, turing.KEEP, turing.LEFT, back),
+			// checks that only ys and zs are left
+			op(checkY, "y", turing.KEEP, turing.RIGHT, checkY),
+			op(checkY, "z", turing.KEEP, turing.RIGHT, checkZ),
+			op(checkY, turing.ANY, turing.KEEP, turing.STAY, turing.RejectState),
+			op(checkZ, "z", turing.KEEP, turing.RIGHT, checkZ),
+			op(checkZ, nil, turing.KEEP, turing.STAY, turing.AcceptState),
+			op(checkZ, turing.ANY, turing.KEEP, turing.STAY, turing.RejectState),
+		),
+		Start:  start,
+		Encode: encodeWord("abc"),
+		Decode: decodeAccepted,
+	}
+}
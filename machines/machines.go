@@ -0,0 +1,167 @@
+// Package machines is a library of reference turing machines, ready to run
+// and tested, to be used as test fixtures and as examples of turing machine
+// programs.
+//
+// Each Machine has its program, the start state, an input encoder that
+// creates the initial tape and an output decoder that reads the result of
+// the halted machine:
+//
+//	increment := machines.BinaryIncrement()
+//	output, err := increment.Run(41, 0) // output is 42
+//
+// Numbers are written on the tape in binary, with the symbols 0 and 1 and
+// the most significant bit first, or in unary, with one symbol 1 per unit.
+// Words are strings, and each digit is written as an int symbol and any
+// other character as a one character string symbol. The outputs are read
+// from the non-blank cells the machine leaves on the tape, except for the
+// recognizers, that halt on turing.AcceptState or turing.RejectState and can
+// also run as a turing.Recognizer.
+package machines
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/massahud/turing"
+)
+
+// Machine is a reference machine.
+type Machine struct {
+	// Name is the machine name.
+	Name string
+	// Program is the machine program.
+	Program *turing.Program
+	// Start is the initial state.
+	Start turing.State
+	// Encode returns the initial tape for the input, starting on position 0,
+	// where the head starts.
+	Encode func(input interface{}) ([]turing.Symbol, error)
+	// Decode returns the output of the halted machine.
+	Decode func(m *turing.Machine) (interface{}, error)
+}
+
+// Definition returns the definition of the machine running on the input.
+func (m *Machine) Definition(input interface{}) (*turing.Definition, error) {
+	tape, err := m.Encode(input)
+	if err != nil {
+		return nil, err
+	}
+	return &turing.Definition{Program: m.Program, Start: m.Start, Tape: tape}, nil
+}
+
+// Run executes the machine on the input and returns the decoded output. A
+// maxSteps smaller than 1 means there is no step limit.
+func (m *Machine) Run(input interface{}, maxSteps int) (interface{}, error) {
+	def, err := m.Definition(input)
+	if err != nil {
+		return nil, err
+	}
+	machine := def.NewMachine()
+	result, err := machine.RunContext(context.Background(), maxSteps)
+	if err != nil {
+		return nil, err
+	}
+	if result.Reason != turing.Halted {
+		return nil, fmt.Errorf("%s did not halt after %d steps", m.Name, result.Steps)
+	}
+	return m.Decode(machine)
+}
+
+// Recognizer returns a recognizer with the machine program, for the
+// recognizers that halt on turing.AcceptState and turing.RejectState.
+func (m *Machine) Recognizer() *turing.Recognizer {
+	return &turing.Recognizer{Program: m.Program, Start: m.Start}
+}
+
+// All returns all the reference machines.
+func All() []*Machine {
+	return []*Machine{
+		BinaryIncrement(),
+		BinaryDecrement(),
+		UnaryAddition(),
+		BinaryAddition(),
+		UnaryMultiplication(),
+		BinaryToUnary(),
+		Copy(),
+		SeparateZeroOne(),
+		Sort(),
+		Palindrome(),
+		BalancedParentheses(),
+		ABC(),
+	}
+}
+
+// op creates an operation, for shorter program tables.
+func op(state turing.State, symbol, write turing.Symbol, movement string, next turing.State) turing.Op {
+	return turing.Op{State: state, Symbol: symbol, WriteSymbol: write, Movement: movement, NextState: next}
+}
+
+// newProgram creates a program with the operations.
+func newProgram(ops ...turing.Op) *turing.Program {
+	program := turing.Program{}
+	for _, op := range ops {
+		program.AddOp(op)
+	}
+	return &program
+}
+
+// pair returns the input as a pair of natural numbers.
+func pair(input interface{}) (int, int, error) {
+	p, ok := input.([2]int)
+	if !ok || p[0] < 0 || p[1] < 0 {
+		return 0, 0, fmt.Errorf("input %v is not a pair of natural numbers", input)
+	}
+	return p[0], p[1], nil
+}
+
+// binary returns the binary symbols of the natural number n.
+func binary(n int) []turing.Symbol {
+	symbols, _ := turing.Binary.Encode(n)
+	return symbols
+}
+
+// unary returns the unary symbols of the natural number n.
+func unary(n int) []turing.Symbol {
+	symbols, _ := turing.Unary.Encode(n)
+	return symbols
+}
+
+// encodeWord returns an encoder of words with characters of the alphabet.
+func encodeWord(alphabet string) func(input interface{}) ([]turing.Symbol, error) {
+	return func(input interface{}) ([]turing.Symbol, error) {
+		word, ok := input.(string)
+		if !ok {
+			return nil, fmt.Errorf("input %v is not a string", input)
+		}
+		for _, r := range word {
+			if !strings.ContainsRune(alphabet, r) {
+				return nil, fmt.Errorf("character %q is not in the alphabet %q", r, alphabet)
+			}
+		}
+		return turing.SymbolString.Encode(word)
+	}
+}
+
+// decode returns a decoder of the non-blank cells the machine leaves on the
+// tape.
+func decode(encoding turing.Encoding) func(m *turing.Machine) (interface{}, error) {
+	return func(m *turing.Machine) (interface{}, error) {
+		output, err := turing.NonBlankTape(m)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.Decode(output)
+	}
+}
+
+// decodeAccepted tells if the recognizer accepted the word.
+func decodeAccepted(m *turing.Machine) (interface{}, error) {
+	switch m.State {
+	case turing.AcceptState:
+		return true, nil
+	case turing.RejectState:
+		return false, nil
+	}
+	return nil, fmt.Errorf("state %v is not the accept or reject state", m.State)
+}
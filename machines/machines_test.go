@@ -0,0 +1,199 @@
+package machines_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/massahud/turing/machines"
+	"github.com/stretchr/testify/assert"
+)
+
+// words returns all the words with characters of the alphabet, up to the
+// length.
+func words(alphabet string, length int) []string {
+	all := []string{""}
+	last := []string{""}
+	for i := 0; i < length; i++ {
+		var next []string
+		for _, word := range last {
+			for _, r := range alphabet {
+				next = append(next, word+string(r))
+			}
+		}
+		all = append(all, next...)
+		last = next
+	}
+	return all
+}
+
+// assertRun runs the machine on the input and compares its output.
+func assertRun(t *testing.T, m *machines.Machine, input, expected interface{}) {
+	output, err := m.Run(input, 100000)
+	if assert.NoError(t, err, "%s: %v", m.Name, input) {
+		assert.Equal(t, expected, output, "%s: %v", m.Name, input)
+	}
+}
+
+func TestMachines(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		t.Log("should have programs without problems")
+
+		names := make(map[string]bool)
+		for _, m := range machines.All() {
+			assert.Empty(t, turing.Validate(m.Program, m.Start), m.Name)
+			assert.False(t, names[m.Name], "duplicated name %s", m.Name)
+			names[m.Name] = true
+		}
+		assert.Len(t, names, 12)
+	})
+
+	t.Run("Numbers", func(t *testing.T) {
+		t.Log("should compute functions of natural numbers")
+
+		for n := 0; n <= 40; n++ {
+			assertRun(t, machines.BinaryIncrement(), n, n+1)
+			assertRun(t, machines.BinaryToUnary(), n, n)
+			if n > 0 {
+				assertRun(t, machines.BinaryDecrement(), n, n-1)
+			}
+		}
+		for a := 0; a <= 8; a++ {
+			for b := 0; b <= 8; b++ {
+				assertRun(t, machines.UnaryAddition(), [2]int{a, b}, a+b)
+				assertRun(t, machines.BinaryAddition(), [2]int{a, b}, a+b)
+				assertRun(t, machines.UnaryMultiplication(), [2]int{a, b}, a*b)
+			}
+		}
+	})
+
+	t.Run("Words", func(t *testing.T) {
+		t.Log("should transform words")
+
+		for _, word := range words("01", 6) {
+			expected := ""
+			if word != "" {
+				expected = word + "_" + word
+			}
+			assertRun(t, machines.Copy(), word, expected)
+
+			separated := strings.Repeat("0", strings.Count(word, "0")) + strings.Repeat("1", strings.Count(word, "1"))
+			assertRun(t, machines.SeparateZeroOne(), word, separated)
+		}
+		for _, word := range words("012", 5) {
+			digits := strings.Split(word, "")
+			sort.Strings(digits)
+			assertRun(t, machines.Sort(), word, strings.Join(digits, ""))
+		}
+	})
+
+	t.Run("Recognizers", func(t *testing.T) {
+		t.Log("should accept only the words of the language")
+
+		for _, word := range words("01", 7) {
+			reversed := []rune(word)
+			for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+				reversed[i], reversed[j] = reversed[j], reversed[i]
+			}
+			assertRun(t, machines.Palindrome(), word, word == string(reversed))
+		}
+		for _, word := range words("()", 8) {
+			depth := 0
+			for _, r := range word {
+				if r == '(' {
+					depth++
+				} else if depth--; depth < 0 {
+					break
+				}
+			}
+			assertRun(t, machines.BalancedParentheses(), word, depth == 0)
+		}
+		for _, word := range words("abc", 7) {
+			n := len(word) / 3
+			expected := word == strings.Repeat("a", n)+strings.Repeat("b", n)+strings.Repeat("c", n)
+			assertRun(t, machines.ABC(), word, expected)
+		}
+
+		parentheses := machines.BalancedParentheses().Recognizer()
+		assert.Empty(t, parentheses.Check([]string{"", "()", "(())()"}, []string{"(", ")(", "(()"}))
+	})
+
+	t.Run("Halting", func(t *testing.T) {
+		t.Log("should halt on the first symbol of the output")
+
+		tests := []struct {
+			machine *machines.Machine
+			input   interface{}
+		}{
+			{machines.BinaryIncrement(), 7},
+			{machines.BinaryDecrement(), 8},
+			{machines.UnaryAddition(), [2]int{2, 3}},
+			{machines.BinaryAddition(), [2]int{5, 6}},
+			{machines.UnaryMultiplication(), [2]int{2, 3}},
+			{machines.BinaryToUnary(), 5},
+			{machines.Sort(), "2101"},
+		}
+		for _, tt := range tests {
+			def, err := tt.machine.Definition(tt.input)
+			if !assert.NoError(t, err, tt.machine.Name) {
+				continue
+			}
+			machine := def.NewMachine()
+			if assert.NoError(t, machine.Run(), tt.machine.Name) {
+				previous, _ := machine.Head.Read()
+				machine.Head.Move(turing.LEFT)
+				blank, _ := machine.Head.Read()
+				assert.NotNil(t, previous, tt.machine.Name)
+				assert.Nil(t, blank, tt.machine.Name)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Log("should not run invalid inputs, and stop after the step limit")
+
+		tests := []struct {
+			machine *machines.Machine
+			input   interface{}
+			err     string
+		}{
+			{machines.BinaryIncrement(), -1, "input -1 is not a natural number"},
+			{machines.BinaryIncrement(), "1", "input 1 is not a natural number"},
+			{machines.BinaryDecrement(), 0, "input 0 is not a positive number"},
+			{machines.UnaryAddition(), 3, "input 3 is not a pair of natural numbers"},
+			{machines.BinaryAddition(), [2]int{1, -1}, "input [1 -1] is not a pair of natural numbers"},
+			{machines.Copy(), 101, "input 101 is not a string"},
+			{machines.Palindrome(), "0120", `character '2' is not in the alphabet "01"`},
+		}
+		for _, tt := range tests {
+			_, err := tt.machine.Run(tt.input, 0)
+			assert.EqualError(t, err, tt.err, tt.machine.Name)
+		}
+
+		_, err := machines.UnaryMultiplication().Run([2]int{5, 5}, 10)
+		assert.EqualError(t, err, "unary multiplication did not halt after 10 steps")
+	})
+}
+
+func Example() {
+	increment := machines.BinaryIncrement()
+	output, err := increment.Run(41, 0)
+	if err != nil {
+		fmt.Println("error:", err.Error())
+		return
+	}
+	fmt.Println(output)
+
+	recognizer := machines.ABC()
+	for _, word := range []string{"aabbcc", "aabbc"} {
+		accepted, _ := recognizer.Run(word, 0)
+		fmt.Println(word, accepted)
+	}
+
+	// Output:
+	// 42
+	// aabbcc true
+	// aabbc false
+}
@@ -0,0 +1,147 @@
+package machines
+
+import (
+	"fmt"
+
+	"github.com/massahud/turing"
+)
+
+// Copy copies a word of 0s and 1s, leaving a blank between the original and
+// the copy, and halts on the blank.
+func Copy() *Machine {
+	// cuts current symbol of the original
+	cut := turing.State{Name: "copy"}
+	// goes to the end of the original with one of the symbols
+	toEnd0 := turing.State{Name: "toEnd0"}
+	toEnd1 := turing.State{Name: "toEnd1"}
+	// writes one of the symbols at the end of the copy
+	write0 := turing.State{Name: "write0"}
+	write1 := turing.State{Name: "write1"}
+	// goes to the start of the copy after writing
+	wrote0 := turing.State{Name: "wrote0"}
+	wrote1 := turing.State{Name: "wrote1"}
+	// goes to where it cut the original to fix it
+	return0 := turing.State{Name: "return0"}
+	return1 := turing.State{Name: "return1"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	return &Machine{
+		Name: "copy",
+		Program: newProgram(
+			op(cut, nil, nil, turing.STAY, halt),
+			op(cut, 0, nil, turing.RIGHT, toEnd0),
+			op(cut, 1, nil, turing.RIGHT, toEnd1),
+			op(toEnd0, nil, nil, turing.RIGHT, write0),
+			op(toEnd0, turing.ANY, turing.KEEP, turing.RIGHT, toEnd0),
+			op(toEnd1, nil, nil, turing.RIGHT, write1),
+			op(toEnd1, turing.ANY, turing.KEEP, turing.RIGHT, toEnd1),
+			op(write0, nil, 0, turing.LEFT, wrote0),
+			op(write0, turing.ANY, turing.KEEP, turing.RIGHT, write0),
+			op(write1, nil, 1, turing.LEFT, wrote1),
+			op(write1, turing.ANY, turing.KEEP, turing.RIGHT, write1),
+			op(wrote0, nil, nil, turing.LEFT, return0),
+			op(wrote0, turing.ANY, turing.KEEP, turing.LEFT, wrote0),
+			op(wrote1, nil, nil, turing.LEFT, return1),
+			op(wrote1, turing.ANY, turing.KEEP, turing.LEFT, wrote1),
+			op(return0, nil, 0, turing.RIGHT, cut),
+			op(return0, turing.ANY, turing.KEEP, turing.LEFT, return0),
+			op(return1, nil, 1, turing.RIGHT, cut),
+			op(return1, turing.ANY, turing.KEEP, turing.LEFT, return1),
+		),
+		Start:  cut,
+		Encode: encodeWord("01"),
+		Decode: decode(turing.SymbolString),
+	}
+}
+
+// SeparateZeroOne moves the 0s of a word of 0s and 1s before its 1s. It
+// moves each 1 to the end of the word, and the 0 it finds there to where the
+// 1 was.
+func SeparateZeroOne() *Machine {
+	get1 := turing.State{Name: "get1"}
+	get0 := turing.State{Name: "get0"}
+	back0 := turing.State{Name: "back0"}
+	back1 := turing.State{Name: "back1"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	return &Machine{
+		Name: "separate 0/1",
+		Program: newProgram(
+			op(get1, 1, nil, turing.RIGHT, get0),
+			op(get1, 0, 0, turing.RIGHT, get1),
+			op(get1, nil, nil, turing.STAY, halt),
+			op(get0, 1, 1, turing.RIGHT, get0),
+			op(get0, 0, 1, turing.LEFT, back0),
+			op(get0, nil, nil, turing.LEFT, back1),
+			op(back0, turing.ANY, turing.KEEP, turing.LEFT, back0),
+			op(back0, nil, 0, turing.RIGHT, get1),
+			op(back1, turing.ANY, turing.KEEP, turing.LEFT, back1),
+			op(back1, nil, 1, turing.STAY, halt),
+		),
+		Start:  get1,
+		Encode: encodeWord("01"),
+		Decode: decode(turing.SymbolString),
+	}
+}
+
+// sortDigits are the digits Sort sorts.
+const sortDigits = 3
+
+// Sort sorts a word of 0s, 1s and 2s with bubble sort, and halts on its first
+// digit. Each pass goes right remembering the previous digit and swapping it
+// with smaller digits, and the passes repeat until one has no swaps.
+func Sort() *Machine {
+	start := turing.State{Name: "start"}
+	rewind := turing.State{Name: "rewind"}
+	done := turing.State{Name: "done"}
+	halt := turing.State{Name: "halt", Halt: true}
+
+	// after the digit p, without and with swaps on the pass; swapped[0] is
+	// not used, as swaps leave a greater digit behind
+	var after, swapped [sortDigits]turing.State
+	for p := range after {
+		after[p] = turing.State{Name: fmt.Sprintf("after%d", p)}
+		swapped[p] = turing.State{Name: fmt.Sprintf("swapped%d", p)}
+	}
+
+	program := newProgram(
+		op(start, nil, turing.KEEP, turing.STAY, halt),
+		// returns to the first digit for the next pass
+		op(rewind, turing.ANY, turing.KEEP, turing.LEFT, rewind),
+		op(rewind, nil, turing.KEEP, turing.RIGHT, start),
+		// returns to the first digit of the sorted word
+		op(done, turing.ANY, turing.KEEP, turing.LEFT, done),
+		op(done, nil, turing.KEEP, turing.RIGHT, halt),
+	)
+	for p := 0; p < sortDigits; p++ {
+		program.AddOp(op(start, p, turing.KEEP, turing.RIGHT, after[p]))
+		// the pass ends on the blank after the word
+		program.AddOp(op(after[p], nil, turing.KEEP, turing.LEFT, done))
+		if p > 0 {
+			program.AddOp(op(swapped[p], nil, turing.KEEP, turing.LEFT, rewind))
+		}
+		for q := 0; q < sortDigits; q++ {
+			if q >= p {
+				program.AddOp(op(after[p], q, turing.KEEP, turing.RIGHT, after[q]))
+				if p > 0 {
+					program.AddOp(op(swapped[p], q, turing.KEEP, turing.RIGHT, swapped[q]))
+				}
+				continue
+			}
+			// writes p and goes back to write q on the previous cell, then
+			// continues after p
+			swap := turing.State{Name: fmt.Sprintf("swap%d%d", q, p)}
+			program.AddOp(op(after[p], q, p, turing.LEFT, swap))
+			program.AddOp(op(swapped[p], q, p, turing.LEFT, swap))
+			program.AddOp(op(swap, turing.ANY, q, turing.RIGHT, swapped[p]))
+		}
+	}
+
+	return &Machine{
+		Name:    "sort",
+		Program: program,
+		Start:   start,
+		Encode:  encodeWord("012"),
+		Decode:  decode(turing.SymbolString),
+	}
+}
@@ -0,0 +1,202 @@
+package turing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	morphettBlank    = "_"
+	morphettWildcard = "*"
+	morphettHalt     = "halt"
+	morphettStart    = "0"
+)
+
+// ParseMorphett reads a program written for the Morphett online turing
+// machine simulator.
+//
+// Each line is a rule "state symbol newSymbol direction newState", and ';'
+// starts a comment. Symbols are single characters, converted to strings,
+// and '_' is the blank symbol. The direction is l, r or '*' to stay.
+//
+// The '*' wildcard is ANY as symbol, KEEP as new symbol and the current
+// state as new state. Wildcard current states are not supported. States
+// whose names start with "halt" are halting states, and the start state is
+// "0", as on the simulator. Breakpoints ('!' after the rule) are ignored.
+func ParseMorphett(r io.Reader) (*Definition, error) {
+	def := Definition{Program: &Program{}, Start: morphettState(morphettStart)}
+	seen := make(map[State]map[Symbol]int)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, ";"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[len(fields)-1] == "!" {
+			fields = fields[:len(fields)-1]
+		}
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("line %d: rule must be \"state symbol newSymbol direction newState\"", line)
+		}
+
+		if fields[0] == morphettWildcard {
+			return nil, fmt.Errorf("line %d: wildcard current state is not supported", line)
+		}
+		op := Op{State: morphettState(fields[0])}
+
+		var err error
+		if op.Symbol, err = parseMorphettSymbol(fields[1], ANY); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+		if op.WriteSymbol, err = parseMorphettSymbol(fields[2], KEEP); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+
+		switch strings.ToLower(fields[3]) {
+		case "l":
+			op.Movement = LEFT
+		case "r":
+			op.Movement = RIGHT
+		case morphettWildcard:
+			op.Movement = STAY
+		default:
+			return nil, fmt.Errorf("line %d: unknown direction %q", line, fields[3])
+		}
+
+		op.NextState = morphettState(fields[4])
+		if fields[4] == morphettWildcard {
+			op.NextState = op.State
+		}
+
+		if seen[op.State] == nil {
+			seen[op.State] = make(map[Symbol]int)
+		}
+		if first, exists := seen[op.State][op.Symbol]; exists {
+			return nil, fmt.Errorf("line %d: duplicated rule for state %s and symbol %s, first defined on line %d",
+				line, fields[0], fields[1], first)
+		}
+		seen[op.State][op.Symbol] = line
+		def.Program.AddOp(op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &def, nil
+}
+
+// FormatMorphett writes the definition program as Morphett simulator rules,
+// ordered by state name and symbol.
+//
+// Symbols must be blank, ANY, KEEP, single character strings or single
+// digit integers, which are read back as strings. Only halting states can
+// have names starting with "halt". When the start state is not "0", it is
+// written in a comment, since the simulator always starts on "0". The
+// initial tape is not written.
+func FormatMorphett(w io.Writer, d *Definition) error {
+	builder := strings.Builder{}
+	if d.Start.Name != morphettStart {
+		fmt.Fprintf(&builder, "; start state: %s\n", d.Start.Name)
+	}
+
+	for _, s := range d.states() {
+		if err := checkMorphettState(s); err != nil {
+			return err
+		}
+	}
+
+	var ops []Op
+	if d.Program != nil {
+		ops = d.Program.SortedOps()
+	}
+	for _, op := range ops {
+		symbol, err := formatMorphettSymbol(op.Symbol, ANY)
+		if err != nil {
+			return fmt.Errorf("op %v %v: symbol: %s", op.State, op.Symbol, err.Error())
+		}
+		write, err := formatMorphettSymbol(op.WriteSymbol, KEEP)
+		if err != nil {
+			return fmt.Errorf("op %v %v: new symbol: %s", op.State, op.Symbol, err.Error())
+		}
+
+		var direction string
+		switch op.Movement {
+		case LEFT:
+			direction = "l"
+		case RIGHT:
+			direction = "r"
+		case STAY:
+			direction = morphettWildcard
+		default:
+			return fmt.Errorf("op %v %v: unknown movement %q", op.State, op.Symbol, op.Movement)
+		}
+
+		fmt.Fprintf(&builder, "%s %s %s %s %s\n", op.State.Name, symbol, write, direction, op.NextState.Name)
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// morphettState creates a state, halting when its name starts with "halt".
+func morphettState(name string) State {
+	return State{Name: name, Halt: strings.HasPrefix(name, morphettHalt)}
+}
+
+func checkMorphettState(s State) error {
+	if s.Name == "" || s.Name == morphettWildcard || strings.ContainsAny(s.Name, "; \t\r\n") {
+		return fmt.Errorf("state name %q is not supported", s.Name)
+	}
+	if s != morphettState(s.Name) {
+		if s.Halt {
+			return fmt.Errorf("halting state %q must start with %q", s.Name, morphettHalt)
+		}
+		return fmt.Errorf("state %q must not start with %q", s.Name, morphettHalt)
+	}
+	return nil
+}
+
+// parseMorphettSymbol converts a rule symbol, '*' is converted to the
+// wildcard symbol.
+func parseMorphettSymbol(text string, wildcard Symbol) (Symbol, error) {
+	switch text {
+	case morphettBlank:
+		return nil, nil
+	case morphettWildcard:
+		return wildcard, nil
+	}
+	if utf8.RuneCountInString(text) != 1 {
+		return nil, fmt.Errorf("symbol %q must be a single character", text)
+	}
+	return text, nil
+}
+
+// formatMorphettSymbol converts a symbol to a rule symbol, the wildcard
+// symbol is converted to '*'.
+func formatMorphettSymbol(s Symbol, wildcard Symbol) (string, error) {
+	if s == wildcard {
+		return morphettWildcard, nil
+	}
+	switch v := s.(type) {
+	case nil:
+		return morphettBlank, nil
+	case int:
+		if v >= 0 && v <= 9 {
+			return strconv.Itoa(v), nil
+		}
+	case string:
+		if utf8.RuneCountInString(v) == 1 && !strings.ContainsAny(v, "_*; \t\r\n") {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("symbol %#v is not supported", s)
+}
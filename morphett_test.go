@@ -0,0 +1,133 @@
+package turing_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMorphett(t *testing.T) {
+	t.Run("Parse", func(t *testing.T) {
+		t.Log("should parse a Morphett program")
+
+		file, err := os.Open("testdata/binary_increment.morphett")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer file.Close()
+
+		def, err := turing.ParseMorphett(file)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		zero := turing.State{"0", false}
+		one := turing.State{"1", false}
+		halt := turing.State{"halt", true}
+
+		assert.Equal(t, zero, def.Start)
+		assert.ElementsMatch(t, []turing.Op{
+			{zero, turing.ANY, turing.KEEP, turing.RIGHT, zero},
+			{zero, nil, nil, turing.LEFT, one},
+			{one, "1", "0", turing.LEFT, one},
+			{one, "0", "1", turing.STAY, halt},
+			{one, nil, "1", turing.STAY, halt},
+		}, def.Program.ListOps())
+
+		def.Tape = []turing.Symbol{"1", "0", "1", "1"}
+		machine := def.NewMachine()
+		if assert.NoError(t, machine.Run()) {
+			assert.Equal(t, " 0: 1\n[1: 1]\n 2: 0\n 3: 0\n", machine.Head.PrintTape(0, 3))
+		}
+	})
+
+	t.Run("ParseWildcardState", func(t *testing.T) {
+		t.Log("should keep the current state for the new state wildcard")
+
+		def, err := turing.ParseMorphett(strings.NewReader("0 a b r *\n"))
+		if assert.NoError(t, err) {
+			zero := turing.State{"0", false}
+			assert.Equal(t, []turing.Op{{zero, "a", "b", turing.RIGHT, zero}}, def.Program.ListOps())
+		}
+	})
+
+	t.Run("ParseErrors", func(t *testing.T) {
+		t.Log("should report unsupported features and invalid rules")
+
+		tests := []struct {
+			text string
+			err  string
+		}{
+			{"* 1 0 r 0", "line 1: wildcard current state is not supported"},
+			{"0 1 0 r", `line 1: rule must be "state symbol newSymbol direction newState"`},
+			{"; comment\n0 10 0 r 0", `line 2: symbol "10" must be a single character`},
+			{"0 1 00 r 0", `line 1: symbol "00" must be a single character`},
+			{"0 1 0 u 0", `line 1: unknown direction "u"`},
+			{"0 1 0 r 0\n0 1 1 l 0", "line 2: duplicated rule for state 0 and symbol 1, first defined on line 1"},
+		}
+		for _, tt := range tests {
+			_, err := turing.ParseMorphett(strings.NewReader(tt.text))
+			assert.EqualError(t, err, tt.err, tt.text)
+		}
+	})
+
+	t.Run("Format", func(t *testing.T) {
+		t.Log("should format a program as Morphett rules")
+
+		file, err := os.Open("testdata/binary_increment.morphett")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer file.Close()
+
+		def, err := turing.ParseMorphett(file)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.FormatMorphett(&builder, def)) {
+			assert.Equal(t, ""+
+				"0 _ _ l 1\n"+
+				"0 * * r 0\n"+
+				"1 _ 1 * halt\n"+
+				"1 0 1 * halt\n"+
+				"1 1 0 l 1\n",
+				builder.String())
+		}
+
+		start, program := createSeparate01()
+		program.AddOp(turing.Op{start, 1, nil, turing.RIGHT, turing.State{"get0", false}})
+
+		builder.Reset()
+		if assert.NoError(t, turing.FormatMorphett(&builder, &turing.Definition{Program: program, Start: start})) {
+			assert.True(t, strings.HasPrefix(builder.String(), "; start state: get1\nback0 _ 0 r get1\n"), builder.String())
+		}
+	})
+
+	t.Run("FormatErrors", func(t *testing.T) {
+		t.Log("should not format programs Morphett can't run")
+
+		state := turing.State{"state", false}
+		tests := []struct {
+			op  turing.Op
+			err string
+		}{
+			{turing.Op{state, "ab", 1, turing.LEFT, state}, `op state ab: symbol: symbol "ab" is not supported`},
+			{turing.Op{state, 1, 10, turing.LEFT, state}, "op state 1: new symbol: symbol 10 is not supported"},
+			{turing.Op{state, 1, 1, "up", state}, `op state 1: unknown movement "up"`},
+			{turing.Op{state, 1, 1, turing.LEFT, turing.State{"end", true}}, `halting state "end" must start with "halt"`},
+			{turing.Op{state, 1, 1, turing.LEFT, turing.State{"halted", false}}, `state "halted" must not start with "halt"`},
+			{turing.Op{state, 1, 1, turing.LEFT, turing.State{"a b", false}}, `state name "a b" is not supported`},
+		}
+		for _, tt := range tests {
+			program := turing.Program{}
+			program.AddOp(tt.op)
+			err := turing.FormatMorphett(&strings.Builder{}, &turing.Definition{Program: &program, Start: state})
+			assert.EqualError(t, err, tt.err)
+		}
+	})
+}
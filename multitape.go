@@ -0,0 +1,321 @@
+package turing
+
+import (
+	"context"
+	"fmt"
+)
+
+// MultiOp encapsulates one operation of a multi-tape turing machine.
+//
+// Given a state and the symbols under each head, sets the symbol under each
+// head, moves each head and changes to another state. Symbols, WriteSymbols
+// and Movements have one entry per tape, in the order of the machine heads.
+//
+// Use ANY on a position of Symbols to make it work for any symbol on that
+// tape.
+//
+// Use KEEP on a position of WriteSymbols to not change the symbol on that
+// tape.
+type MultiOp struct {
+	State        State
+	Symbols      []Symbol
+	WriteSymbols []Symbol
+	Movements    []string
+	NextState    State
+}
+
+// matches tells if the operation symbols match the symbols under the heads.
+// It also returns how many of the symbols were matched by ANY.
+func (op MultiOp) matches(symbols []Symbol) (bool, int) {
+	anys := 0
+	for i, s := range op.Symbols {
+		if s == ANY {
+			anys++
+			continue
+		}
+		if s != symbols[i] {
+			return false, 0
+		}
+	}
+	return true, anys
+}
+
+// sameSymbols tells if both operations match the same symbols.
+func (op MultiOp) sameSymbols(other MultiOp) bool {
+	for i := range op.Symbols {
+		if op.Symbols[i] != other.Symbols[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MultiProgram stores the operations of a multi-tape machine, based on the
+// current state and the symbols under the heads.
+//
+// The number of tapes is defined by the first operation added.
+type MultiProgram struct {
+	tapes  int
+	ops    map[State][]MultiOp
+	length int
+}
+
+// Tapes returns the number of tapes the program operates on.
+func (p *MultiProgram) Tapes() int {
+	return p.tapes
+}
+
+// FindOp returns the operation for the state and the symbols under the heads.
+//
+// When more than one operation matches, the one with less ANY symbols is
+// returned. If they have the same number of ANY symbols, the first added
+// operation is returned.
+func (p *MultiProgram) FindOp(state State, symbols []Symbol) (MultiOp, error) {
+	stateOps := p.ops[state]
+	if len(stateOps) == 0 {
+		return MultiOp{}, newKindError(ErrNoStateOp, "no operation for state %v", state)
+	}
+	if len(symbols) != p.tapes {
+		return MultiOp{}, fmt.Errorf("expected %d symbols, got %d", p.tapes, len(symbols))
+	}
+
+	found := -1
+	foundAnys := 0
+	for i, op := range stateOps {
+		ok, anys := op.matches(symbols)
+		if ok && (found < 0 || anys < foundAnys) {
+			found = i
+			foundAnys = anys
+		}
+	}
+	if found < 0 {
+		return MultiOp{}, newKindError(ErrNoSymbolOp, "no operation for state %v and symbols %v", state, symbols)
+	}
+
+	return stateOps[found], nil
+}
+
+// ListOps returns a list of operations on the machine, grouped by state.
+// The states are not ordered in any way.
+func (p *MultiProgram) ListOps() []MultiOp {
+	opList := make([]MultiOp, 0, p.length)
+	for _, stateOps := range p.ops {
+		opList = append(opList, stateOps...)
+	}
+	return opList
+}
+
+// AddOp adds or rewrite a State-Symbols operation.
+//
+// It returns an error if the operation does not have one symbol, write
+// symbol and movement for each tape of the program.
+func (p *MultiProgram) AddOp(op MultiOp) error {
+	tapes := p.tapes
+	if tapes == 0 {
+		tapes = len(op.Symbols)
+	}
+	if tapes == 0 {
+		return fmt.Errorf("operation for state %v has no tapes", op.State)
+	}
+	if len(op.Symbols) != tapes || len(op.WriteSymbols) != tapes || len(op.Movements) != tapes {
+		return fmt.Errorf("operation for state %v must have %d symbols, write symbols and movements", op.State, tapes)
+	}
+
+	if p.ops == nil {
+		p.ops = make(map[State][]MultiOp)
+	}
+	p.tapes = tapes
+
+	stateOps := p.ops[op.State]
+	for i := range stateOps {
+		if stateOps[i].sameSymbols(op) {
+			stateOps[i] = op
+			return nil
+		}
+	}
+	p.ops[op.State] = append(stateOps, op)
+	p.length++
+	return nil
+}
+
+// MultiStepEvent describes one step executed by a MultiMachine. The
+// symbols and positions have one entry per head.
+type MultiStepEvent struct {
+	// Step is the step number, the first step of a machine is 1.
+	Step int
+	// State is the machine state before the step.
+	State State
+	// Read are the symbols under the heads before the step.
+	Read []Symbol
+	// Op is the operation executed.
+	Op MultiOp
+	// Written are the symbols left under the heads.
+	Written []Symbol
+	// From are the head positions before the step.
+	From []int
+	// To are the head positions after the step.
+	To []int
+	// NextState is the machine state after the step.
+	NextState State
+}
+
+// MultiObserver receives the steps executed by a MultiMachine.
+type MultiObserver interface {
+	ObserveMulti(e MultiStepEvent)
+}
+
+// MultiObserverFunc adapts a function to the MultiObserver interface.
+type MultiObserverFunc func(e MultiStepEvent)
+
+// ObserveMulti calls f(e).
+func (f MultiObserverFunc) ObserveMulti(e MultiStepEvent) {
+	f(e)
+}
+
+// MultiMachine is a multi-tape turing machine, it has one head per tape, a
+// program to execute and the initial state.
+type MultiMachine struct {
+	Heads   []*Head
+	Program *MultiProgram
+	State   State
+	// Observer receives every executed step, when set.
+	Observer MultiObserver
+
+	steps int
+}
+
+// Steps returns the number of steps the machine executed.
+func (m *MultiMachine) Steps() int {
+	return m.steps
+}
+
+// Step executes one step of the machine. The error is a *StepError, with the
+// configuration where the step failed.
+func (m *MultiMachine) Step() error {
+	if m.State.Halt {
+		return m.stepError(nil, newKindError(ErrHalted, "machine is halted, state %s", m.State.String()))
+	}
+	if len(m.Heads) != m.Program.Tapes() {
+		return m.stepError(nil, fmt.Errorf("machine has %d heads, program expects %d tapes", len(m.Heads), m.Program.Tapes()))
+	}
+
+	symbols := make([]Symbol, len(m.Heads))
+	for i, head := range m.Heads {
+		v, err := head.Read()
+		if err != nil {
+			return m.stepError(nil, wrapKindError(ErrTapeRead, err))
+		}
+		symbols[i] = v
+	}
+
+	oper, err := m.Program.FindOp(m.State, symbols)
+	if err != nil {
+		return m.stepError(symbols, err)
+	}
+
+	from := m.positions()
+	destinations := make([]int, len(m.Heads))
+	for i, head := range m.Heads {
+		pos, err := head.destination(oper.Movements[i])
+		if err != nil {
+			return m.stepError(symbols, err)
+		}
+		destinations[i] = pos
+	}
+	// the writes are checked before writing any of them, and undone if a
+	// tape still fails, so a failed step does not change the tapes
+	for i, head := range m.Heads {
+		if oper.WriteSymbols[i] != KEEP {
+			if err := head.writable(oper.WriteSymbols[i]); err != nil {
+				return m.stepError(symbols, wrapKindError(ErrTapeWrite, err))
+			}
+		}
+	}
+	written := make([]Symbol, len(m.Heads))
+	for i, head := range m.Heads {
+		written[i] = symbols[i]
+		if oper.WriteSymbols[i] == KEEP {
+			continue
+		}
+		if err := head.Write(oper.WriteSymbols[i]); err != nil {
+			return m.stepError(symbols, m.rollback(i, oper, symbols, wrapKindError(ErrTapeWrite, err)))
+		}
+		written[i] = oper.WriteSymbols[i]
+	}
+	for i, head := range m.Heads {
+		head.moveTo(destinations[i])
+	}
+	state := m.State
+	m.State = oper.NextState
+	m.steps++
+
+	if m.Observer != nil {
+		m.Observer.ObserveMulti(MultiStepEvent{
+			Step:      m.steps,
+			State:     state,
+			Read:      symbols,
+			Op:        oper,
+			Written:   written,
+			From:      from,
+			To:        m.positions(),
+			NextState: m.State,
+		})
+	}
+	return nil
+}
+
+// rollback writes back the symbols read on the tapes before the failed one,
+// and returns the write error with the errors of the tapes it could not
+// restore.
+func (m *MultiMachine) rollback(failed int, oper MultiOp, symbols []Symbol, err error) error {
+	for i := 0; i < failed; i++ {
+		if oper.WriteSymbols[i] == KEEP {
+			continue
+		}
+		if restoreErr := m.Heads[i].Write(symbols[i]); restoreErr != nil {
+			err = &kindError{
+				kind:  ErrTapeWrite,
+				msg:   fmt.Sprintf("%s, and tape %d was not restored: %s", err.Error(), i, restoreErr.Error()),
+				cause: err,
+			}
+		}
+	}
+	return err
+}
+
+// positions returns the head positions.
+func (m *MultiMachine) positions() []int {
+	positions := make([]int, len(m.Heads))
+	for i, head := range m.Heads {
+		positions[i] = head.Pos()
+	}
+	return positions
+}
+
+// stepError creates the error of the next step, with the symbols under the
+// heads.
+func (m *MultiMachine) stepError(symbols []Symbol, err error) error {
+	return &StepError{Step: m.steps + 1, State: m.State, Symbols: symbols, Positions: m.positions(), Err: err}
+}
+
+// Run executes the current program until it reaches a halt state or there is no
+// operation for current state and symbols under the heads.
+func (m *MultiMachine) Run() error {
+	for instr := 1; !m.State.Halt; instr++ {
+		err := m.Step()
+		if err != nil {
+			return fmt.Errorf("Error at instruction %d: %w", instr, err)
+		}
+	}
+	return nil
+}
+
+// RunContext executes the current program like Run, but also stops when ctx
+// is done or after maxSteps steps, as Machine.RunContext does.
+func (m *MultiMachine) RunContext(ctx context.Context, maxSteps int) (RunResult, error) {
+	return runContext(ctx, maxSteps, m)
+}
+
+func (m *MultiMachine) halted() bool {
+	return m.State.Halt
+}
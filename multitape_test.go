@@ -0,0 +1,353 @@
+package turing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+// createPalindrome creates a two tape program that copies the [01]* input to
+// the second tape and compares it backwards with the first one.
+func createPalindrome() (turing.State, *turing.MultiProgram) {
+	cp := turing.State{"copy", false}
+	rewind := turing.State{"rewind", false}
+	compare := turing.State{"compare", false}
+	accept := turing.State{"accept", true}
+	reject := turing.State{"reject", true}
+
+	keep := []turing.Symbol{turing.KEEP, turing.KEEP}
+
+	program := turing.MultiProgram{}
+	program.AddOp(turing.MultiOp{cp, []turing.Symbol{0, nil}, []turing.Symbol{turing.KEEP, 0}, []string{turing.RIGHT, turing.RIGHT}, cp})
+	program.AddOp(turing.MultiOp{cp, []turing.Symbol{1, nil}, []turing.Symbol{turing.KEEP, 1}, []string{turing.RIGHT, turing.RIGHT}, cp})
+	program.AddOp(turing.MultiOp{cp, []turing.Symbol{nil, nil}, keep, []string{turing.LEFT, turing.LEFT}, rewind})
+
+	program.AddOp(turing.MultiOp{rewind, []turing.Symbol{turing.ANY, turing.ANY}, keep, []string{turing.LEFT, turing.STAY}, rewind})
+	program.AddOp(turing.MultiOp{rewind, []turing.Symbol{nil, turing.ANY}, keep, []string{turing.RIGHT, turing.STAY}, compare})
+
+	program.AddOp(turing.MultiOp{compare, []turing.Symbol{0, 0}, keep, []string{turing.RIGHT, turing.LEFT}, compare})
+	program.AddOp(turing.MultiOp{compare, []turing.Symbol{1, 1}, keep, []string{turing.RIGHT, turing.LEFT}, compare})
+	program.AddOp(turing.MultiOp{compare, []turing.Symbol{nil, nil}, keep, []string{turing.STAY, turing.STAY}, accept})
+	program.AddOp(turing.MultiOp{compare, []turing.Symbol{turing.ANY, turing.ANY}, keep, []string{turing.STAY, turing.STAY}, reject})
+
+	return cp, &program
+}
+
+func TestMultiProgram(t *testing.T) {
+	t.Run("FindOp", func(t *testing.T) {
+		t.Log("should find the operation with less ANY symbols")
+
+		state := turing.State{"state", false}
+		keep := []turing.Symbol{turing.KEEP, turing.KEEP}
+		stay := []string{turing.STAY, turing.STAY}
+
+		anyAny := turing.MultiOp{state, []turing.Symbol{turing.ANY, turing.ANY}, keep, stay, state}
+		anyOne := turing.MultiOp{state, []turing.Symbol{turing.ANY, 1}, keep, stay, state}
+		oneAny := turing.MultiOp{state, []turing.Symbol{1, turing.ANY}, keep, stay, state}
+		zeroZero := turing.MultiOp{state, []turing.Symbol{0, 0}, keep, stay, state}
+
+		program := turing.MultiProgram{}
+		for _, op := range []turing.MultiOp{anyAny, anyOne, oneAny, zeroZero} {
+			assert.NoError(t, program.AddOp(op))
+		}
+
+		tests := []struct {
+			symbols []turing.Symbol
+			op      turing.MultiOp
+		}{
+			{[]turing.Symbol{0, 0}, zeroZero},
+			{[]turing.Symbol{0, 1}, anyOne},
+			{[]turing.Symbol{1, 0}, oneAny},
+			{[]turing.Symbol{1, 1}, anyOne},
+			{[]turing.Symbol{nil, nil}, anyAny},
+		}
+		for _, tt := range tests {
+			op, err := program.FindOp(state, tt.symbols)
+			if assert.NoErrorf(t, err, "%v", tt.symbols) {
+				assert.Equalf(t, tt.op, op, "%v", tt.symbols)
+			}
+		}
+	})
+
+	t.Run("AddAndList", func(t *testing.T) {
+		t.Log("should rewrite operations with the same symbols")
+
+		state := turing.State{"state", false}
+		halt := turing.State{"halt", true}
+		keep := []turing.Symbol{turing.KEEP, turing.KEEP}
+		stay := []string{turing.STAY, turing.STAY}
+
+		op1 := turing.MultiOp{state, []turing.Symbol{0, 1}, keep, stay, state}
+		op2 := turing.MultiOp{state, []turing.Symbol{0, 1}, keep, stay, halt}
+		op3 := turing.MultiOp{halt, []turing.Symbol{0, 1}, keep, stay, state}
+
+		program := turing.MultiProgram{}
+		assert.NoError(t, program.AddOp(op1))
+		assert.NoError(t, program.AddOp(op2))
+		assert.NoError(t, program.AddOp(op3))
+
+		ops := program.ListOps()
+		assert.Len(t, ops, 2)
+		assert.Contains(t, ops, op2)
+		assert.Contains(t, ops, op3)
+		assert.Equal(t, 2, program.Tapes())
+	})
+
+	t.Run("AddInvalid", func(t *testing.T) {
+		t.Log("should not add operations with a wrong number of tapes")
+
+		state := turing.State{"state", false}
+
+		program := turing.MultiProgram{}
+		err := program.AddOp(turing.MultiOp{state, nil, nil, nil, state})
+		assert.EqualError(t, err, "operation for state state has no tapes")
+
+		err = program.AddOp(turing.MultiOp{state, []turing.Symbol{0, 1}, []turing.Symbol{0}, []string{turing.LEFT, turing.LEFT}, state})
+		assert.EqualError(t, err, "operation for state state must have 2 symbols, write symbols and movements")
+
+		assert.NoError(t, program.AddOp(turing.MultiOp{state, []turing.Symbol{0}, []turing.Symbol{0}, []string{turing.LEFT}, state}))
+		err = program.AddOp(turing.MultiOp{state, []turing.Symbol{0, 1}, []turing.Symbol{0, 1}, []string{turing.LEFT, turing.LEFT}, state})
+		assert.EqualError(t, err, "operation for state state must have 1 symbols, write symbols and movements")
+	})
+
+	t.Run("NoOp", func(t *testing.T) {
+		t.Log("should return error when there is no op for the state or symbols")
+
+		state := turing.State{"state", false}
+		dummy := turing.State{"dummy", false}
+
+		program := turing.MultiProgram{}
+		program.AddOp(turing.MultiOp{state, []turing.Symbol{0, 1}, []turing.Symbol{0, 1}, []string{turing.LEFT, turing.LEFT}, state})
+
+		_, err := program.FindOp(dummy, []turing.Symbol{0, 1})
+		assert.EqualError(t, err, "no operation for state dummy")
+
+		_, err = program.FindOp(state, []turing.Symbol{1, 1})
+		assert.EqualError(t, err, "no operation for state state and symbols [1 1]")
+
+		_, err = program.FindOp(state, []turing.Symbol{0})
+		assert.EqualError(t, err, "expected 2 symbols, got 1")
+	})
+}
+
+// writeOnceTape is a tape that fails all writes after the first one.
+type writeOnceTape struct {
+	mapTape
+	written bool
+}
+
+func (t *writeOnceTape) Set(pos int, symbols ...turing.Symbol) error {
+	if t.written {
+		return errors.New("tape is write once")
+	}
+	t.written = true
+	return t.mapTape.Set(pos, symbols...)
+}
+
+func TestMultiMachine(t *testing.T) {
+	t.Run("Step", func(t *testing.T) {
+		t.Log("should write and move each tape independently")
+
+		state := turing.State{"state", false}
+		newState := turing.State{"newstate", false}
+
+		tape1 := turing.NewInfiniteTape()
+		tape1.Set(0, 1)
+		tape2 := turing.NewInfiniteTape()
+		tape2.Set(0, 2)
+
+		head1 := turing.Head{}
+		head1.Attach(tape1, 0)
+		head2 := turing.Head{}
+		head2.Attach(tape2, 0)
+
+		program := turing.MultiProgram{}
+		program.AddOp(turing.MultiOp{state, []turing.Symbol{1, turing.ANY}, []turing.Symbol{turing.KEEP, 3}, []string{turing.LEFT, turing.RIGHT}, newState})
+
+		machine := turing.MultiMachine{Heads: []*turing.Head{&head1, &head2}, Program: &program, State: state}
+
+		if assert.NoError(t, machine.Step()) {
+			v, _ := tape1.Get(0)
+			assert.Equal(t, 1, v)
+			v, _ = tape2.Get(0)
+			assert.Equal(t, 3, v)
+			assert.Equal(t, -1, head1.Pos())
+			assert.Equal(t, 1, head2.Pos())
+			assert.Equal(t, newState, machine.State)
+		}
+	})
+
+	t.Run("StepErrors", func(t *testing.T) {
+		t.Log("should error on halted machines and wrong number of heads")
+
+		state := turing.State{"state", false}
+		halt := turing.State{"halt", true}
+
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+
+		program := turing.MultiProgram{}
+		program.AddOp(turing.MultiOp{state, []turing.Symbol{0, 1}, []turing.Symbol{0, 1}, []string{turing.LEFT, turing.LEFT}, state})
+
+		machine := turing.MultiMachine{Heads: []*turing.Head{&head}, Program: &program, State: state}
+		assert.EqualError(t, machine.Step(), "machine has 1 heads, program expects 2 tapes")
+
+		machine.State = halt
+		err := machine.Step()
+		assert.EqualError(t, err, "machine is halted, state [halt]")
+		assert.True(t, errors.Is(err, turing.ErrHalted))
+
+		head2 := turing.Head{}
+		head2.Attach(turing.NewInfiniteTape(), 3)
+		machine = turing.MultiMachine{Heads: []*turing.Head{&head, &head2}, Program: &program, State: state}
+		err = machine.Step()
+		assert.True(t, errors.Is(err, turing.ErrNoSymbolOp))
+		var stepErr *turing.StepError
+		if assert.True(t, errors.As(err, &stepErr)) {
+			assert.Equal(t, turing.StepError{Step: 1, State: state, Symbols: []turing.Symbol{nil, nil}, Positions: []int{0, 3}, Err: stepErr.Err}, *stepErr)
+		}
+	})
+
+	t.Run("StepWriteErrors", func(t *testing.T) {
+		t.Log("should not write any tape when a write fails")
+
+		state := turing.State{"state", false}
+		program := turing.MultiProgram{}
+		program.AddOp(turing.MultiOp{state, []turing.Symbol{0, ">"}, []turing.Symbol{1, 1}, []string{turing.RIGHT, turing.STAY}, state})
+		program.AddOp(turing.MultiOp{state, []turing.Symbol{0, 0}, []turing.Symbol{1, 1}, []string{turing.RIGHT, turing.STAY}, state})
+
+		bounded := turing.NewBoundedTape(0, 0, nil, ">")
+		failing := failTape{mapTape: mapTape{0: 0}, failRead: -1, failWrite: 0}
+		for _, tape2 := range []turing.Tape{bounded, failing} {
+			tape1 := turing.NewInfiniteTape()
+			tape1.Set(0, 0)
+			head1 := turing.Head{}
+			head1.Attach(tape1, 0)
+			head2 := turing.Head{}
+			head2.Attach(tape2, 0)
+			if tape2 == bounded {
+				head2.Move(turing.RIGHT)
+			}
+
+			machine := turing.MultiMachine{Heads: []*turing.Head{&head1, &head2}, Program: &program, State: state}
+			err := machine.Step()
+			assert.True(t, errors.Is(err, turing.ErrTapeWrite), err)
+
+			v, _ := tape1.Get(0)
+			assert.Equal(t, 0, v)
+			assert.Equal(t, 0, head1.Pos())
+			assert.Equal(t, state, machine.State)
+		}
+	})
+
+	t.Run("StepRollbackErrors", func(t *testing.T) {
+		t.Log("should return the errors of the tapes that were not restored")
+
+		state := turing.State{"state", false}
+		program := turing.MultiProgram{}
+		program.AddOp(turing.MultiOp{state, []turing.Symbol{0, 0}, []turing.Symbol{1, 1}, []string{turing.RIGHT, turing.RIGHT}, state})
+
+		head1 := turing.Head{}
+		head1.Attach(&writeOnceTape{mapTape: mapTape{0: 0}}, 0)
+		head2 := turing.Head{}
+		head2.Attach(failTape{mapTape: mapTape{0: 0}, failRead: -1, failWrite: 0}, 0)
+
+		machine := turing.MultiMachine{Heads: []*turing.Head{&head1, &head2}, Program: &program, State: state}
+		err := machine.Step()
+		assert.EqualError(t, err, "can't write position 0, and tape 0 was not restored: tape is write once")
+		assert.True(t, errors.Is(err, turing.ErrTapeWrite))
+	})
+
+	t.Run("Observer", func(t *testing.T) {
+		t.Log("should send every executed step to the observer")
+
+		start, program := createPalindrome()
+
+		input := turing.NewInfiniteTape()
+		input.Set(0, 1, 0)
+
+		head1 := turing.Head{}
+		head1.Attach(input, 0)
+		head2 := turing.Head{}
+		head2.Attach(turing.NewInfiniteTape(), 0)
+
+		var events []turing.MultiStepEvent
+		machine := turing.MultiMachine{Heads: []*turing.Head{&head1, &head2}, Program: program, State: start}
+		machine.Observer = turing.MultiObserverFunc(func(e turing.MultiStepEvent) {
+			events = append(events, e)
+		})
+		if !assert.NoError(t, machine.Run()) {
+			return
+		}
+
+		if assert.Len(t, events, machine.Steps()) {
+			first := events[0]
+			assert.Equal(t, 1, first.Step)
+			assert.Equal(t, start, first.State)
+			assert.Equal(t, []turing.Symbol{1, nil}, first.Read)
+			assert.Equal(t, []int{0, 0}, first.From)
+			assert.Equal(t, []int{1, 1}, first.To)
+			last := events[len(events)-1]
+			assert.Equal(t, machine.State, last.NextState)
+		}
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		t.Log("should run a two tape palindrome program")
+
+		tests := []struct {
+			word   []turing.Symbol
+			accept bool
+		}{
+			{[]turing.Symbol{}, true},
+			{[]turing.Symbol{0}, true},
+			{[]turing.Symbol{0, 1}, false},
+			{[]turing.Symbol{1, 0, 1}, true},
+			{[]turing.Symbol{1, 0, 0, 1}, true},
+			{[]turing.Symbol{1, 0, 1, 1}, false},
+		}
+		for _, tt := range tests {
+			start, program := createPalindrome()
+
+			input := turing.NewInfiniteTape()
+			input.Set(0, tt.word...)
+
+			head1 := turing.Head{}
+			head1.Attach(input, 0)
+			head2 := turing.Head{}
+			head2.Attach(turing.NewInfiniteTape(), 0)
+
+			machine := turing.MultiMachine{Heads: []*turing.Head{&head1, &head2}, Program: program, State: start}
+
+			if assert.NoErrorf(t, machine.Run(), "%v", tt.word) {
+				assert.Equalf(t, tt.accept, machine.State.Name == "accept", "%v", tt.word)
+			}
+		}
+	})
+
+	t.Run("RunContext", func(t *testing.T) {
+		t.Log("should stop after the maximum number of steps")
+
+		start, program := createPalindrome()
+
+		input := turing.NewInfiniteTape()
+		input.Set(0, 1, 1, 1, 1)
+
+		head1 := turing.Head{}
+		head1.Attach(input, 0)
+		head2 := turing.Head{}
+		head2.Attach(turing.NewInfiniteTape(), 0)
+
+		machine := turing.MultiMachine{Heads: []*turing.Head{&head1, &head2}, Program: program, State: start}
+
+		result, err := machine.RunContext(context.Background(), 3)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.RunResult{Steps: 3, Reason: turing.BudgetExhausted}, result)
+			assert.Equal(t, 3, head1.Pos())
+			assert.Equal(t, 3, head2.Pos())
+		}
+	})
+}
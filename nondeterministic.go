@@ -0,0 +1,266 @@
+package turing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NondeterministicProgram stores the operations of a nondeterministic
+// machine, based on current state and symbol under head.
+//
+// Unlike Program, a State-Symbol tuple can have several operations, and the
+// machine follows all of them.
+type NondeterministicProgram struct {
+	ops    map[State]map[Symbol][]Op
+	length int
+}
+
+// FindOps returns the operations for the State-Symbol tuple.
+//
+// As in Program.FindOp, the ANY operations are only returned when there is
+// no operation for the symbol.
+func (p *NondeterministicProgram) FindOps(state State, symbol Symbol) ([]Op, error) {
+	symbolMap := p.ops[state]
+	if symbolMap == nil {
+		return nil, newKindError(ErrNoStateOp, "no operation for state %v", state)
+	}
+
+	opers, exists := symbolMap[symbol]
+	if !exists {
+		opers, exists = symbolMap[ANY]
+		if !exists {
+			return nil, newKindError(ErrNoSymbolOp, "no operation for state %v and symbol %v", state, symbol)
+		}
+	}
+
+	return opers, nil
+}
+
+// ListOps returns a list of operations on the machine.
+// They are not ordered in any way
+func (p *NondeterministicProgram) ListOps() []Op {
+	opList := make([]Op, 0, p.length)
+	for _, symbolMap := range p.ops {
+		for _, opers := range symbolMap {
+			opList = append(opList, opers...)
+		}
+	}
+	return opList
+}
+
+// AddOp adds an operation to the State-Symbol tuple. Adding an operation
+// that already exists does nothing.
+func (p *NondeterministicProgram) AddOp(op Op) {
+	if p.ops == nil {
+		p.ops = make(map[State]map[Symbol][]Op)
+	}
+	symbolMap, ok := p.ops[op.State]
+	if !ok {
+		symbolMap = make(map[Symbol][]Op)
+		p.ops[op.State] = symbolMap
+	}
+
+	for _, existing := range symbolMap[op.Symbol] {
+		if existing == op {
+			return
+		}
+	}
+	symbolMap[op.Symbol] = append(symbolMap[op.Symbol], op)
+	p.length++
+}
+
+// DefaultExploreConfigurations is the maximum number of configurations
+// explored when ExploreLimits.MaxConfigurations is not set.
+const DefaultExploreConfigurations = 1000000
+
+// ExploreLimits bounds the search of the configuration tree.
+type ExploreLimits struct {
+	// MaxDepth is the maximum number of steps of a path. There is no depth
+	// limit when it is smaller than 1.
+	MaxDepth int
+	// MaxConfigurations is the maximum number of configurations explored.
+	// It is DefaultExploreConfigurations when smaller than 1.
+	MaxConfigurations int
+}
+
+// ExploreResult is the outcome of a configuration tree search.
+type ExploreResult struct {
+	// Accepted informs if an accepting state is reachable.
+	Accepted bool
+	// State is the accepting state reached.
+	State State
+	// Path is the list of operations from the start configuration to the
+	// accepting one.
+	Path []Op
+	// Configurations is the number of configurations explored.
+	Configurations int
+	// Truncated informs if the search stopped because of the limits before
+	// exploring the whole tree.
+	Truncated bool
+}
+
+// cellWrite is a symbol written on a configuration. The writes are a
+// persistent list shared by the configurations of a branch, from the last
+// write to the first one.
+type cellWrite struct {
+	pos    int
+	symbol Symbol
+	prev   *cellWrite
+}
+
+// configuration is a node of the configuration tree. Its writes are the
+// symbols written since the start, the other positions are read from the
+// original tape.
+type configuration struct {
+	state  State
+	pos    int
+	writes *cellWrite
+	parent *configuration
+	op     Op
+	depth  int
+}
+
+func (c *configuration) read(tape Tape) (Symbol, error) {
+	for w := c.writes; w != nil; w = w.prev {
+		if w.pos == c.pos {
+			return w.symbol, nil
+		}
+	}
+	return tape.Get(c.pos)
+}
+
+// next creates the configuration after executing op.
+func (c *configuration) next(op Op, symbol Symbol) *configuration {
+	writes := c.writes
+	if op.WriteSymbol != KEEP && op.WriteSymbol != symbol {
+		writes = &cellWrite{pos: c.pos, symbol: op.WriteSymbol, prev: writes}
+	}
+
+	pos := c.pos
+	switch op.Movement {
+	case LEFT:
+		pos--
+	case RIGHT:
+		pos++
+	}
+
+	return &configuration{
+		state:  op.NextState,
+		pos:    pos,
+		writes: writes,
+		parent: c,
+		op:     op,
+		depth:  c.depth + 1,
+	}
+}
+
+// key identifies the configuration state, head position and written cells,
+// to find the configurations already visited.
+func (c *configuration) key() string {
+	cells := make(map[int]Symbol)
+	for w := c.writes; w != nil; w = w.prev {
+		if _, ok := cells[w.pos]; !ok {
+			cells[w.pos] = w.symbol
+		}
+	}
+	positions := make([]int, 0, len(cells))
+	for pos := range cells {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions)
+
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "%q %t %d", c.state.Name, c.state.Halt, c.pos)
+	for _, pos := range positions {
+		fmt.Fprintf(&builder, " %d:%#v", pos, cells[pos])
+	}
+	return builder.String()
+}
+
+func (c *configuration) path() []Op {
+	path := make([]Op, c.depth)
+	for n := c; n.parent != nil; n = n.parent {
+		path[n.depth-1] = n.op
+	}
+	return path
+}
+
+// Explore searches breadth first the configuration tree of a nondeterministic
+// program, starting on the state with the head at pos of the tape.
+//
+// The search stops on the first accepting state found, which is reached by
+// one of the shortest paths. When no accepting states are given, every
+// halting state but RejectState is accepting. Branches that halt on other
+// states, or without an operation to execute, are rejected. The tape is only
+// read, all writes are kept on the configurations. Configurations already
+// visited by another branch are not explored again.
+func Explore(program *NondeterministicProgram, state State, tape Tape, pos int, limits ExploreLimits, accept ...State) (ExploreResult, error) {
+	maxConfigurations := limits.MaxConfigurations
+	if maxConfigurations < 1 {
+		maxConfigurations = DefaultExploreConfigurations
+	}
+
+	result := ExploreResult{}
+	start := &configuration{state: state, pos: pos}
+	queue := []*configuration{start}
+	visited := map[string]bool{start.key(): true}
+
+	for len(queue) > 0 {
+		if result.Configurations >= maxConfigurations {
+			result.Truncated = true
+			return result, nil
+		}
+
+		conf := queue[0]
+		queue[0] = nil
+		queue = queue[1:]
+		result.Configurations++
+
+		if conf.state.Halt {
+			if !exploreAccepting(conf.state, accept) {
+				continue
+			}
+			result.Accepted = true
+			result.State = conf.state
+			result.Path = conf.path()
+			return result, nil
+		}
+
+		symbol, err := conf.read(tape)
+		if err != nil {
+			return result, err
+		}
+		opers, err := program.FindOps(conf.state, symbol)
+		if err != nil {
+			continue
+		}
+		if limits.MaxDepth > 0 && conf.depth >= limits.MaxDepth {
+			result.Truncated = true
+			continue
+		}
+		for _, op := range opers {
+			next := conf.next(op, symbol)
+			if key := next.key(); !visited[key] {
+				visited[key] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// exploreAccepting informs if the halting state is one of the accepting
+// states, or is not RejectState when there are none.
+func exploreAccepting(s State, accept []State) bool {
+	if len(accept) == 0 {
+		return s != RejectState
+	}
+	for _, a := range accept {
+		if s == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+// createContains11 creates a nondeterministic program that accepts [01]*
+// strings with two consecutive 1's, guessing where they start.
+func createContains11() (turing.State, *turing.NondeterministicProgram) {
+	scan := turing.State{"scan", false}
+	one := turing.State{"one", false}
+	accept := turing.State{"accept", true}
+
+	program := turing.NondeterministicProgram{}
+	program.AddOp(turing.Op{scan, 0, turing.KEEP, turing.RIGHT, scan})
+	program.AddOp(turing.Op{scan, 1, turing.KEEP, turing.RIGHT, scan})
+	program.AddOp(turing.Op{scan, 1, turing.KEEP, turing.RIGHT, one})
+	program.AddOp(turing.Op{one, 1, turing.KEEP, turing.STAY, accept})
+
+	return scan, &program
+}
+
+func TestNondeterministicProgram(t *testing.T) {
+	t.Run("AddAndList", func(t *testing.T) {
+		t.Log("should keep several operations for the same state and symbol")
+
+		state := turing.State{"state", false}
+		halt := turing.State{"halt", true}
+
+		op1 := turing.Op{state, 0, 0, turing.RIGHT, state}
+		op2 := turing.Op{state, 0, 1, turing.LEFT, state}
+		op3 := turing.Op{state, nil, nil, turing.STAY, halt}
+
+		program := turing.NondeterministicProgram{}
+		program.AddOp(op1)
+		program.AddOp(op2)
+		program.AddOp(op3)
+		program.AddOp(op1)
+
+		ops := program.ListOps()
+		assert.Len(t, ops, 3)
+		assert.Contains(t, ops, op1)
+		assert.Contains(t, ops, op2)
+		assert.Contains(t, ops, op3)
+	})
+
+	t.Run("FindOps", func(t *testing.T) {
+		t.Log("should find all operations, using ANY when there is no symbol match")
+
+		state := turing.State{"state", false}
+		dummy := turing.State{"dummy", false}
+
+		op1 := turing.Op{state, 0, 0, turing.RIGHT, state}
+		op2 := turing.Op{state, 0, 1, turing.LEFT, state}
+		op3 := turing.Op{state, turing.ANY, turing.KEEP, turing.STAY, state}
+
+		program := turing.NondeterministicProgram{}
+		program.AddOp(op1)
+		program.AddOp(op2)
+
+		_, err := program.FindOps(state, 1)
+		assert.EqualError(t, err, "no operation for state state and symbol 1")
+
+		_, err = program.FindOps(dummy, 1)
+		assert.EqualError(t, err, "no operation for state dummy")
+
+		program.AddOp(op3)
+
+		ops, err := program.FindOps(state, 0)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []turing.Op{op1, op2}, ops)
+		}
+
+		ops, err = program.FindOps(state, 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []turing.Op{op3}, ops)
+		}
+	})
+}
+
+func TestExplore(t *testing.T) {
+	t.Run("Accepted", func(t *testing.T) {
+		t.Log("should find the shortest path to a halting state")
+
+		start, program := createContains11()
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 0, 1, 0, 1, 1, 0)
+
+		result, err := turing.Explore(program, start, tape, 0, turing.ExploreLimits{})
+		if assert.NoError(t, err) {
+			assert.True(t, result.Accepted)
+			assert.False(t, result.Truncated)
+			assert.Equal(t, "accept", result.State.Name)
+
+			scan := turing.State{"scan", false}
+			one := turing.State{"one", false}
+			assert.Equal(t, []turing.Op{
+				{scan, 0, turing.KEEP, turing.RIGHT, scan},
+				{scan, 1, turing.KEEP, turing.RIGHT, scan},
+				{scan, 0, turing.KEEP, turing.RIGHT, scan},
+				{scan, 1, turing.KEEP, turing.RIGHT, one},
+				{one, 1, turing.KEEP, turing.STAY, result.State},
+			}, result.Path)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		t.Log("should explore the whole tree when no halting state is reachable")
+
+		start, program := createContains11()
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 1, 0, 1, 0, 1)
+
+		result, err := turing.Explore(program, start, tape, 0, turing.ExploreLimits{})
+		if assert.NoError(t, err) {
+			assert.False(t, result.Accepted)
+			assert.False(t, result.Truncated)
+			assert.Nil(t, result.Path)
+			assert.Equal(t, 9, result.Configurations)
+		}
+	})
+
+	t.Run("Writes", func(t *testing.T) {
+		t.Log("should keep the writes of each branch apart and not change the tape")
+
+		guess := turing.State{"guess", false}
+		check := turing.State{"check", false}
+		halt := turing.State{"halt", true}
+
+		program := turing.NondeterministicProgram{}
+		program.AddOp(turing.Op{guess, nil, "a", turing.LEFT, check})
+		program.AddOp(turing.Op{guess, nil, "b", turing.LEFT, check})
+		program.AddOp(turing.Op{check, turing.ANY, turing.KEEP, turing.RIGHT, check})
+		program.AddOp(turing.Op{check, "b", turing.KEEP, turing.STAY, halt})
+
+		tape := turing.NewInfiniteTape()
+
+		result, err := turing.Explore(&program, guess, tape, 0, turing.ExploreLimits{MaxDepth: 10})
+		if assert.NoError(t, err) {
+			assert.True(t, result.Accepted)
+			assert.Len(t, result.Path, 3)
+			assert.Equal(t, "b", result.Path[0].WriteSymbol)
+
+			v, _ := tape.Get(0)
+			assert.Nil(t, v)
+		}
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		t.Log("should stop the search on the limits")
+
+		walk := turing.State{"walk", false}
+		halt := turing.State{"halt", true}
+
+		program := turing.NondeterministicProgram{}
+		program.AddOp(turing.Op{walk, nil, turing.KEEP, turing.RIGHT, walk})
+		program.AddOp(turing.Op{walk, nil, turing.KEEP, turing.LEFT, walk})
+		program.AddOp(turing.Op{walk, 1, turing.KEEP, turing.STAY, halt})
+
+		result, err := turing.Explore(&program, walk, turing.NewInfiniteTape(), 0, turing.ExploreLimits{MaxDepth: 3})
+		if assert.NoError(t, err) {
+			assert.False(t, result.Accepted)
+			assert.True(t, result.Truncated)
+			assert.Equal(t, 7, result.Configurations)
+		}
+
+		result, err = turing.Explore(&program, walk, turing.NewInfiniteTape(), 0, turing.ExploreLimits{MaxConfigurations: 10})
+		if assert.NoError(t, err) {
+			assert.False(t, result.Accepted)
+			assert.True(t, result.Truncated)
+			assert.Equal(t, 10, result.Configurations)
+		}
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(-2, 1)
+
+		result, err = turing.Explore(&program, walk, tape, 0, turing.ExploreLimits{MaxDepth: 3})
+		if assert.NoError(t, err) {
+			assert.True(t, result.Accepted)
+			assert.Len(t, result.Path, 3)
+		}
+	})
+
+	t.Run("Visited", func(t *testing.T) {
+		t.Log("should not explore the same configuration twice and stop on looping branches")
+
+		left := turing.State{"left", false}
+		right := turing.State{"right", false}
+
+		program := turing.NondeterministicProgram{}
+		program.AddOp(turing.Op{left, turing.ANY, 1, turing.RIGHT, right})
+		program.AddOp(turing.Op{left, turing.ANY, 0, turing.RIGHT, right})
+		program.AddOp(turing.Op{right, turing.ANY, turing.KEEP, turing.LEFT, left})
+
+		result, err := turing.Explore(&program, left, turing.NewInfiniteTape(), 0, turing.ExploreLimits{})
+		if assert.NoError(t, err) {
+			assert.False(t, result.Accepted)
+			assert.False(t, result.Truncated)
+			assert.Equal(t, 5, result.Configurations)
+		}
+	})
+
+	t.Run("AcceptingStates", func(t *testing.T) {
+		t.Log("should not accept on RejectState or on halting states that are not accepting")
+
+		guess := turing.State{"guess", false}
+		other := turing.State{"other", true}
+
+		program := turing.NondeterministicProgram{}
+		program.AddOp(turing.Op{guess, nil, turing.KEEP, turing.STAY, turing.RejectState})
+		program.AddOp(turing.Op{guess, nil, turing.KEEP, turing.RIGHT, other})
+		program.AddOp(turing.Op{guess, nil, turing.KEEP, turing.LEFT, turing.AcceptState})
+
+		result, err := turing.Explore(&program, guess, turing.NewInfiniteTape(), 0, turing.ExploreLimits{})
+		if assert.NoError(t, err) {
+			assert.True(t, result.Accepted)
+			assert.Equal(t, other, result.State)
+			assert.Equal(t, 3, result.Configurations)
+		}
+
+		result, err = turing.Explore(&program, guess, turing.NewInfiniteTape(), 0, turing.ExploreLimits{}, turing.AcceptState)
+		if assert.NoError(t, err) {
+			assert.True(t, result.Accepted)
+			assert.Equal(t, turing.AcceptState, result.State)
+			assert.Equal(t, 4, result.Configurations)
+		}
+
+		program = turing.NondeterministicProgram{}
+		program.AddOp(turing.Op{guess, nil, turing.KEEP, turing.STAY, turing.RejectState})
+
+		result, err = turing.Explore(&program, guess, turing.NewInfiniteTape(), 0, turing.ExploreLimits{})
+		if assert.NoError(t, err) {
+			assert.False(t, result.Accepted)
+			assert.False(t, result.Truncated)
+			assert.Nil(t, result.Path)
+			assert.Equal(t, 2, result.Configurations)
+		}
+	})
+}
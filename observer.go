@@ -0,0 +1,83 @@
+package turing
+
+import (
+	"fmt"
+	"io"
+)
+
+// StepEvent describes one step executed by a Machine.
+type StepEvent struct {
+	// Step is the step number, the first step of a machine is 1.
+	Step int
+	// State is the machine state before the step.
+	State State
+	// Read is the symbol under the head before the step.
+	Read Symbol
+	// Op is the operation executed.
+	Op Op
+	// Written is the symbol left under the head. It is the read symbol when
+	// the operation KEEPs it.
+	Written Symbol
+	// From is the head position before the step.
+	From int
+	// To is the head position after the step.
+	To int
+	// NextState is the machine state after the step.
+	NextState State
+}
+
+// Observer receives the steps executed by a Machine.
+type Observer interface {
+	Observe(e StepEvent)
+}
+
+// Rewinder is an Observer that keeps the steps it observed. When a Timeline
+// takes the machine back, it calls Rewind on the machine observer, so the
+// steps executed again are not observed twice.
+type Rewinder interface {
+	// Rewind forgets the steps after step.
+	Rewind(step int)
+}
+
+// ObserverFunc adapts a function to the Observer interface.
+type ObserverFunc func(e StepEvent)
+
+// Observe calls f(e).
+func (f ObserverFunc) Observe(e StepEvent) {
+	f(e)
+}
+
+// Observers sends the steps to all of its observers, in order.
+type Observers []Observer
+
+// Observe sends the step to all observers.
+func (o Observers) Observe(e StepEvent) {
+	for _, observer := range o {
+		observer.Observe(e)
+	}
+}
+
+// Rewind rewinds the observers that are Rewinders.
+func (o Observers) Rewind(step int) {
+	for _, observer := range o {
+		if r, ok := observer.(Rewinder); ok {
+			r.Rewind(step)
+		}
+	}
+}
+
+// traceWriter writes one line per step.
+type traceWriter struct {
+	w io.Writer
+}
+
+// NewTraceWriter creates an observer that writes one line for each step
+// into w. Write errors are ignored.
+func NewTraceWriter(w io.Writer) Observer {
+	return traceWriter{w: w}
+}
+
+func (t traceWriter) Observe(e StepEvent) {
+	fmt.Fprintf(t.w, "%d: %v %v -> %v %s %v (pos %d -> %d)\n",
+		e.Step, e.State, e.Read, e.Written, e.Op.Movement, e.NextState, e.From, e.To)
+}
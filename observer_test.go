@@ -0,0 +1,112 @@
+package turing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserver(t *testing.T) {
+	t.Run("Events", func(t *testing.T) {
+		t.Log("should receive every step of the machine")
+
+		state := turing.State{"state", false}
+		back := turing.State{"back", false}
+		halt := turing.State{"halt", true}
+
+		write := turing.Op{state, 1, 0, turing.RIGHT, state}
+		keep := turing.Op{state, nil, turing.KEEP, turing.LEFT, back}
+		stop := turing.Op{back, turing.ANY, turing.KEEP, turing.STAY, halt}
+
+		program := turing.Program{}
+		program.AddOp(write)
+		program.AddOp(keep)
+		program.AddOp(stop)
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 1)
+
+		head := turing.Head{}
+		head.Attach(tape, 0)
+
+		var events []turing.StepEvent
+		observer := turing.ObserverFunc(func(e turing.StepEvent) {
+			events = append(events, e)
+		})
+
+		machine := turing.Machine{Head: &head, Program: &program, State: state, Observer: observer}
+
+		if assert.NoError(t, machine.Run()) {
+			assert.Equal(t, []turing.StepEvent{
+				{Step: 1, State: state, Read: 1, Op: write, Written: 0, From: 0, To: 1, NextState: state},
+				{Step: 2, State: state, Read: nil, Op: keep, Written: nil, From: 1, To: 0, NextState: back},
+				{Step: 3, State: back, Read: 0, Op: stop, Written: 0, From: 0, To: 0, NextState: halt},
+			}, events)
+			assert.Equal(t, 3, machine.Steps())
+		}
+	})
+
+	t.Run("NoOp", func(t *testing.T) {
+		t.Log("should not receive steps that failed")
+
+		state := turing.State{"state", false}
+
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+
+		calls := 0
+		observer := turing.ObserverFunc(func(e turing.StepEvent) {
+			calls++
+		})
+
+		machine := turing.Machine{Head: &head, Program: &turing.Program{}, State: state, Observer: observer}
+
+		assert.Error(t, machine.Step())
+		assert.Equal(t, 0, calls)
+		assert.Equal(t, 0, machine.Steps())
+	})
+
+	t.Run("Observers", func(t *testing.T) {
+		t.Log("should send the step to all observers in order")
+
+		var calls []string
+		observers := turing.Observers{
+			turing.ObserverFunc(func(e turing.StepEvent) { calls = append(calls, "first") }),
+			turing.ObserverFunc(func(e turing.StepEvent) { calls = append(calls, "second") }),
+		}
+
+		observers.Observe(turing.StepEvent{})
+
+		assert.Equal(t, []string{"first", "second"}, calls)
+	})
+
+	t.Run("TraceWriter", func(t *testing.T) {
+		t.Log("should write one line per step")
+
+		zeroAll := turing.State{"zero all", false}
+		halt := turing.State{"halt", true}
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 1, 0)
+
+		head := turing.Head{}
+		head.Attach(tape, 0)
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{zeroAll, turing.ANY, 0, turing.RIGHT, zeroAll})
+		program.AddOp(turing.Op{zeroAll, nil, nil, turing.STAY, halt})
+
+		builder := strings.Builder{}
+		machine := turing.Machine{Head: &head, Program: &program, State: zeroAll, Observer: turing.NewTraceWriter(&builder)}
+
+		if assert.NoError(t, machine.Run()) {
+			assert.Equal(t, ""+
+				"1: zero all 1 -> 0 right zero all (pos 0 -> 1)\n"+
+				"2: zero all 0 -> 0 right zero all (pos 1 -> 2)\n"+
+				"3: zero all <nil> -> <nil> stay [halt] (pos 2 -> 2)\n",
+				builder.String())
+		}
+	})
+}
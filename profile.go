@@ -0,0 +1,221 @@
+package turing
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Profile is the execution profile of a run.
+type Profile struct {
+	// Steps is the number of steps executed.
+	Steps int
+	// States is the number of steps executed on each state.
+	States map[State]int
+	// Ops is the number of times each operation was executed.
+	Ops map[Op]int
+	// CellsVisited is the number of distinct positions the head was on.
+	CellsVisited int
+	// NonBlankCells is the number of cells with a non blank symbol at the
+	// end of the run.
+	NonBlankCells int
+	// Reversals is the number of times the head changed its direction.
+	Reversals int
+	// Duration is the time from the profiler creation to the last step.
+	Duration time.Duration
+}
+
+// Profiler is an Observer that builds the execution profile of a run.
+type Profiler struct {
+	machine   *Machine
+	profile   Profile
+	visited   map[int]bool
+	direction int
+	start     time.Time
+	last      time.Time
+
+	// first is the machine step when the profiler was created, and history
+	// has what each step after it added to the profile, to rewind it.
+	first   int
+	history []profileStep
+}
+
+// profileStep is what a step added to the profile.
+type profileStep struct {
+	op        Op
+	to        int
+	visited   bool
+	reversal  bool
+	direction int
+}
+
+// NewProfiler creates a profiler for the machine, starting on its current
+// configuration. It must be set as the machine observer, or be one of its
+// observers.
+func NewProfiler(m *Machine) *Profiler {
+	now := time.Now()
+	return &Profiler{
+		machine: m,
+		profile: Profile{States: make(map[State]int), Ops: make(map[Op]int)},
+		visited: map[int]bool{m.Head.Pos(): true},
+		start:   now,
+		last:    now,
+		first:   m.steps,
+	}
+}
+
+// Observe records the step.
+func (p *Profiler) Observe(e StepEvent) {
+	p.last = time.Now()
+	p.profile.Steps++
+	p.profile.States[e.State]++
+	p.profile.Ops[e.Op]++
+	step := profileStep{op: e.Op, to: e.To, visited: !p.visited[e.To], direction: p.direction}
+	p.visited[e.To] = true
+
+	direction := e.To - e.From
+	if direction != 0 {
+		if p.direction != 0 && direction != p.direction {
+			p.profile.Reversals++
+			step.reversal = true
+		}
+		p.direction = direction
+	}
+	p.history = append(p.history, step)
+}
+
+// Rewind removes the steps after step from the profile, for a Timeline that
+// takes the machine back.
+func (p *Profiler) Rewind(step int) {
+	n := step - p.first
+	if n < 0 {
+		n = 0
+	}
+	for len(p.history) > n {
+		last := p.history[len(p.history)-1]
+		p.history = p.history[:len(p.history)-1]
+
+		p.profile.Steps--
+		if p.profile.States[last.op.State]--; p.profile.States[last.op.State] == 0 {
+			delete(p.profile.States, last.op.State)
+		}
+		if p.profile.Ops[last.op]--; p.profile.Ops[last.op] == 0 {
+			delete(p.profile.Ops, last.op)
+		}
+		if last.visited {
+			delete(p.visited, last.to)
+		}
+		if last.reversal {
+			p.profile.Reversals--
+		}
+		p.direction = last.direction
+	}
+}
+
+// Profile returns the profile of the steps recorded so far. The non blank
+// cells are counted on the current machine tape.
+func (p *Profiler) Profile() Profile {
+	profile := p.profile
+	profile.States = make(map[State]int, len(p.profile.States))
+	for s, n := range p.profile.States {
+		profile.States[s] = n
+	}
+	profile.Ops = make(map[Op]int, len(p.profile.Ops))
+	for op, n := range p.profile.Ops {
+		profile.Ops[op] = n
+	}
+	profile.CellsVisited = len(p.visited)
+	profile.NonBlankCells = countNonBlank(p.machine.Head)
+	profile.Duration = p.last.Sub(p.start)
+	return profile
+}
+
+// countNonBlank counts the non blank cells the head visited, and the ones
+// the tape knows about.
+func countNonBlank(h *Head) int {
+	from, to := h.minPos, h.maxPos
+	if tape, ok := h.tape.(extentTape); ok {
+		if min, max, nonBlank := tape.extent(); nonBlank {
+			if min < from {
+				from = min
+			}
+			if max > to {
+				to = max
+			}
+		}
+	}
+	count := 0
+	for pos := from; pos <= to; pos++ {
+		if s, err := h.tape.Get(pos); err == nil && s != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// WriteTable writes the profile as a table, with the totals followed by the
+// states and the operations, the most executed first:
+//
+//	steps            33
+//	...
+//
+//	state  steps  %
+//	get1   10     30.3%
+//	...
+//
+//	state  op     next   count  %
+//	get0   1/1,R  get0   7      21.2%
+//	...
+func (p Profile) WriteTable(w io.Writer) error {
+	percent := func(n int) string {
+		if p.Steps == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(p.Steps))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "steps\t%d\n", p.Steps)
+	fmt.Fprintf(tw, "cells visited\t%d\n", p.CellsVisited)
+	fmt.Fprintf(tw, "non blank cells\t%d\n", p.NonBlankCells)
+	fmt.Fprintf(tw, "reversals\t%d\n", p.Reversals)
+	fmt.Fprintf(tw, "time\t%v\n", p.Duration)
+
+	states := make([]State, 0, len(p.States))
+	for s := range p.States {
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		a, b := states[i], states[j]
+		if p.States[a] != p.States[b] {
+			return p.States[a] > p.States[b]
+		}
+		return compareStates(a, b) < 0
+	})
+	fmt.Fprint(tw, "\nstate\tsteps\t%\n")
+	for _, s := range states {
+		fmt.Fprintf(tw, "%v\t%d\t%s\n", s, p.States[s], percent(p.States[s]))
+	}
+
+	ops := make([]Op, 0, len(p.Ops))
+	for op := range p.Ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		a, b := ops[i], ops[j]
+		if p.Ops[a] != p.Ops[b] {
+			return p.Ops[a] > p.Ops[b]
+		}
+		if c := compareStates(a.State, b.State); c != 0 {
+			return c < 0
+		}
+		return compareSymbols(a.Symbol, b.Symbol) < 0
+	})
+	fmt.Fprint(tw, "\nstate\top\tnext\tcount\t%\n")
+	for _, op := range ops {
+		fmt.Fprintf(tw, "%v\t%s\t%v\t%d\t%s\n", op.State, diagramLabel(op), op.NextState, p.Ops[op], percent(p.Ops[op]))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,105 @@
+package turing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiler(t *testing.T) {
+	createMachine := func() *turing.Machine {
+		start, program := createSeparate01()
+		def := turing.Definition{Program: program, Start: start, Tape: []turing.Symbol{0, 1, 0, 1, 0, 1, 1, 1, 0, 1}, HeadPos: 2}
+		return def.NewMachine()
+	}
+
+	t.Run("Profile", func(t *testing.T) {
+		t.Log("should count the steps, states, operations, cells and reversals")
+
+		machine := createMachine()
+		profiler := turing.NewProfiler(machine)
+		machine.Observer = profiler
+		if !assert.NoError(t, machine.Run()) {
+			return
+		}
+
+		profile := profiler.Profile()
+		assert.True(t, profile.Duration >= 0)
+		profile.Duration = 0
+
+		get1 := turing.State{Name: "get1"}
+		get0 := turing.State{Name: "get0"}
+		back0 := turing.State{Name: "back0"}
+		back1 := turing.State{Name: "back1"}
+		halt := turing.State{Name: "halt", Halt: true}
+		assert.Equal(t, turing.Profile{
+			Steps:  24,
+			States: map[turing.State]int{get1: 4, get0: 10, back0: 5, back1: 5},
+			Ops: map[turing.Op]int{
+				{State: get1, Symbol: 1, WriteSymbol: nil, Movement: turing.RIGHT, NextState: get0}:                   3,
+				{State: get1, Symbol: 0, WriteSymbol: 0, Movement: turing.RIGHT, NextState: get1}:                     1,
+				{State: get0, Symbol: 1, WriteSymbol: 1, Movement: turing.RIGHT, NextState: get0}:                     7,
+				{State: get0, Symbol: 0, WriteSymbol: 1, Movement: turing.LEFT, NextState: back0}:                     2,
+				{State: get0, Symbol: nil, WriteSymbol: nil, Movement: turing.LEFT, NextState: back1}:                 1,
+				{State: back0, Symbol: turing.ANY, WriteSymbol: turing.KEEP, Movement: turing.LEFT, NextState: back0}: 3,
+				{State: back0, Symbol: nil, WriteSymbol: 0, Movement: turing.RIGHT, NextState: get1}:                  2,
+				{State: back1, Symbol: turing.ANY, WriteSymbol: turing.KEEP, Movement: turing.LEFT, NextState: back1}: 4,
+				{State: back1, Symbol: nil, WriteSymbol: 1, Movement: turing.STAY, NextState: halt}:                   1,
+			},
+			CellsVisited:  9,
+			NonBlankCells: 10,
+			Reversals:     5,
+		}, profile)
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		t.Log("should not change returned profiles with new steps")
+
+		machine := createMachine()
+		profiler := turing.NewProfiler(machine)
+		machine.Observer = profiler
+		machine.Step()
+		profile := profiler.Profile()
+		machine.Step()
+
+		assert.Equal(t, 1, profile.Steps)
+		assert.Equal(t, map[turing.State]int{{Name: "get1"}: 1}, profile.States)
+		assert.Equal(t, 2, profiler.Profile().Steps)
+	})
+
+	t.Run("WriteTable", func(t *testing.T) {
+		t.Log("should write the profile as a table, most executed first")
+
+		state := turing.State{"state", false}
+		halt := turing.State{"halt", true}
+		right := turing.Op{state, nil, "x", turing.RIGHT, state}
+		stop := turing.Op{state, "y", turing.KEEP, turing.STAY, halt}
+		profile := turing.Profile{
+			Steps:         4,
+			States:        map[turing.State]int{state: 4},
+			Ops:           map[turing.Op]int{right: 3, stop: 1},
+			CellsVisited:  4,
+			NonBlankCells: 4,
+		}
+
+		builder := strings.Builder{}
+		if assert.NoError(t, profile.WriteTable(&builder)) {
+			assert.Equal(t, ""+
+				"steps            4\n"+
+				"cells visited    4\n"+
+				"non blank cells  4\n"+
+				"reversals        0\n"+
+				"time             0s\n"+
+				"\n"+
+				"state  steps  %\n"+
+				"state  4      100.0%\n"+
+				"\n"+
+				"state  op     next    count  %\n"+
+				"state  _/x,R  state   3      75.0%\n"+
+				"state  y/*,S  [halt]  1      25.0%\n",
+				builder.String())
+		}
+	})
+}
@@ -1,6 +1,10 @@
 package turing
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // Op encapsulates one turing machine operation.
 //
@@ -24,18 +28,19 @@ type Program struct {
 	length int
 }
 
-// FindOp returns the current operation for the State-Symbol tuple.
+// FindOp returns the current operation for the State-Symbol tuple. The
+// error matches ErrNoStateOp or ErrNoSymbolOp.
 func (p *Program) FindOp(state State, symbol Symbol) (Op, error) {
 	symbolMap := p.ops[state]
 	if symbolMap == nil {
-		return Op{}, fmt.Errorf("no operation for state %v", state)
+		return Op{}, newKindError(ErrNoStateOp, "no operation for state %v", state)
 	}
 
 	oper, exists := symbolMap[symbol]
 	if !exists {
 		oper, exists = symbolMap[ANY]
 		if !exists {
-			return Op{}, fmt.Errorf("no operation for state %v and symbol %v", state, symbol)
+			return Op{}, newKindError(ErrNoSymbolOp, "no operation for state %v and symbol %v", state, symbol)
 		}
 	}
 
@@ -70,3 +75,86 @@ func (p *Program) AddOp(op Op) {
 	}
 	symbolMap[op.Symbol] = op
 }
+
+// SortedOps returns a list of operations on the machine, ordered by state
+// name and symbol.
+//
+// The blank symbol comes first, followed by integers, strings, symbols of
+// other types and ANY.
+func (p *Program) SortedOps() []Op {
+	opList := p.ListOps()
+	sort.Slice(opList, func(i, j int) bool {
+		a, b := opList[i], opList[j]
+		if a.State != b.State {
+			return compareStates(a.State, b.State) < 0
+		}
+		return compareSymbols(a.Symbol, b.Symbol) < 0
+	})
+	return opList
+}
+
+// compareStates orders states by name, non halting first.
+func compareStates(a, b State) int {
+	if a.Name != b.Name {
+		return strings.Compare(a.Name, b.Name)
+	}
+	if a.Halt == b.Halt {
+		return 0
+	}
+	if b.Halt {
+		return -1
+	}
+	return 1
+}
+
+// sortStates sorts the states with compareStates.
+func sortStates(states []State) {
+	sort.Slice(states, func(i, j int) bool {
+		return compareStates(states[i], states[j]) < 0
+	})
+}
+
+// symbolRank is the position of the symbol type on the symbol ordering.
+func symbolRank(s Symbol) int {
+	switch v := s.(type) {
+	case nil:
+		return 0
+	case int:
+		return 1
+	case string:
+		if v == ANY {
+			return 4
+		}
+		return 2
+	default:
+		return 3
+	}
+}
+
+// compareSymbols orders symbols: blank, integers, strings, other types
+// and ANY.
+func compareSymbols(a, b Symbol) int {
+	ra, rb := symbolRank(a), symbolRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	switch ra {
+	case 1:
+		x, y := a.(int), b.(int)
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+		return 0
+	case 2:
+		return strings.Compare(a.(string), b.(string))
+	case 3:
+		if c := strings.Compare(fmt.Sprintf("%T", a), fmt.Sprintf("%T", b)); c != 0 {
+			return c
+		}
+		return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+	}
+	return 0
+}
@@ -0,0 +1,179 @@
+package turing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+var (
+	// AcceptState is the halting state recognizers accept words on, unless
+	// they have other accepting states.
+	AcceptState = State{Name: "accept", Halt: true}
+	// RejectState is the halting state recognizers reject words on. Any other
+	// halting state that is not accepting also rejects.
+	RejectState = State{Name: "reject", Halt: true}
+)
+
+// DefaultRecognizerSteps is the step budget of recognizer runs when
+// Recognizer.MaxSteps is not set.
+const DefaultRecognizerSteps = 1000000
+
+// Outcome is the result of a recognizer run.
+type Outcome int
+
+const (
+	// Undecided means the machine did not halt within the step budget. It is
+	// the zero value, so an outcome that was not set never accepts.
+	Undecided Outcome = iota
+	// Accepted means the machine halted on an accepting state.
+	Accepted
+	// Rejected means the machine halted on a state that is not accepting.
+	Rejected
+	// Failed means the machine stopped with an error, without an operation
+	// for the state and symbol or out of the tape bounds.
+	Failed
+)
+
+var outcomeNames = map[Outcome]string{
+	Undecided: "undecided",
+	Accepted:  "accepted",
+	Rejected:  "rejected",
+	Failed:    "failed",
+}
+
+// String returns the outcome description.
+func (o Outcome) String() string {
+	if name, ok := outcomeNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("Outcome(%d)", int(o))
+}
+
+// Recognizer runs a program on words to decide if they belong to a language.
+// Each word is written on a new infinite tape from position 0, where the head
+// starts, and the machine accepts it by halting on an accepting state.
+type Recognizer struct {
+	// Program is the machine program.
+	Program *Program
+	// Start is the initial state.
+	Start State
+	// Accept are the accepting states. When it is empty, only AcceptState
+	// is accepting.
+	Accept []State
+	// MaxSteps is the step budget of each run. It is
+	// DefaultRecognizerSteps when smaller than 1.
+	MaxSteps int
+}
+
+// Recognize runs the machine on the word. Each character of the word is a
+// symbol: the character itself as a string, or its integer value when it is
+// a digit the program uses as an int, and _ is the blank symbol.
+//
+// The error is the Step error when the outcome is Failed, otherwise it is
+// nil.
+func (r *Recognizer) Recognize(word string) (Outcome, error) {
+	return r.RecognizeSymbols(r.symbols(word))
+}
+
+// RecognizeSymbols runs the machine on the word symbols, like Recognize.
+func (r *Recognizer) RecognizeSymbols(word []Symbol) (Outcome, error) {
+	def := Definition{Program: r.Program, Start: r.Start, Tape: word}
+	maxSteps := r.MaxSteps
+	if maxSteps < 1 {
+		maxSteps = DefaultRecognizerSteps
+	}
+
+	machine := def.NewMachine()
+	result, err := machine.RunContext(context.Background(), maxSteps)
+	if err != nil {
+		return Failed, err
+	}
+	if result.Reason != Halted {
+		return Undecided, nil
+	}
+	if r.accepting(machine.State) {
+		return Accepted, nil
+	}
+	return Rejected, nil
+}
+
+func (r *Recognizer) accepting(s State) bool {
+	if len(r.Accept) == 0 {
+		return s == AcceptState
+	}
+	for _, accept := range r.Accept {
+		if s == accept {
+			return true
+		}
+	}
+	return false
+}
+
+// symbols converts the word characters to the symbols of the program.
+func (r *Recognizer) symbols(word string) []Symbol {
+	ints := make(map[Symbol]bool)
+	texts := make(map[Symbol]bool)
+	for _, op := range r.Program.ListOps() {
+		for _, s := range []Symbol{op.Symbol, op.WriteSymbol} {
+			switch s.(type) {
+			case int:
+				ints[s] = true
+			case string:
+				texts[s] = true
+			}
+		}
+	}
+
+	var symbols []Symbol
+	for _, c := range word {
+		text := string(c)
+		if text == "_" {
+			symbols = append(symbols, nil)
+		} else if n, err := strconv.Atoi(text); err == nil && (ints[n] || !texts[text]) {
+			symbols = append(symbols, n)
+		} else {
+			symbols = append(symbols, text)
+		}
+	}
+	return symbols
+}
+
+// Mismatch is a word with an unexpected recognizer outcome.
+type Mismatch struct {
+	// Word is the word.
+	Word string
+	// Expected is the expected outcome.
+	Expected Outcome
+	// Actual is the outcome of the run.
+	Actual Outcome
+	// Err is the run error, when the outcome is Failed.
+	Err error
+}
+
+// String describes the mismatch.
+func (m Mismatch) String() string {
+	text := fmt.Sprintf("word %q: expected %v, got %v", m.Word, m.Expected, m.Actual)
+	if m.Err != nil {
+		text += ": " + m.Err.Error()
+	}
+	return text
+}
+
+// Check runs the machine on words of the language, that it must accept, and
+// on words out of the language, that it must reject. It returns the words
+// with other outcomes, in the order they were given.
+func (r *Recognizer) Check(accepted, rejected []string) []Mismatch {
+	var mismatches []Mismatch
+	check := func(words []string, expected Outcome) {
+		for _, word := range words {
+			actual, err := r.Recognize(word)
+			if actual != expected {
+				mismatches = append(mismatches, Mismatch{Word: word, Expected: expected, Actual: actual, Err: err})
+			}
+		}
+	}
+	check(accepted, Accepted)
+	check(rejected, Rejected)
+	return mismatches
+}
@@ -0,0 +1,124 @@
+package turing_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/massahud/turing/machines"
+	"github.com/stretchr/testify/assert"
+)
+
+// evenZeros returns a recognizer of the words of 0s with an even length, that
+// fails on 1s and never halts on 2s.
+func evenZeros() *turing.Recognizer {
+	even := turing.State{"even", false}
+	odd := turing.State{"odd", false}
+	forever := turing.State{"forever", false}
+	program := turing.Program{}
+	for _, op := range []turing.Op{
+		{even, 0, turing.KEEP, turing.RIGHT, odd},
+		{even, nil, turing.KEEP, turing.STAY, turing.AcceptState},
+		{even, 2, turing.KEEP, turing.STAY, forever},
+		{odd, 0, turing.KEEP, turing.RIGHT, even},
+		{odd, nil, turing.KEEP, turing.STAY, turing.RejectState},
+		{odd, 2, turing.KEEP, turing.STAY, forever},
+		{forever, turing.ANY, turing.KEEP, turing.STAY, forever},
+	} {
+		program.AddOp(op)
+	}
+	return &turing.Recognizer{Program: &program, Start: even, MaxSteps: 100}
+}
+
+func TestRecognizer(t *testing.T) {
+	t.Run("Outcomes", func(t *testing.T) {
+		t.Log("should accept and reject words on the accept and reject states")
+
+		r := evenZeros()
+		tests := []struct {
+			word    string
+			outcome turing.Outcome
+		}{
+			{"", turing.Accepted},
+			{"00", turing.Accepted},
+			{"0000", turing.Accepted},
+			{"0", turing.Rejected},
+			{"000", turing.Rejected},
+			{"001", turing.Failed},
+			{"02", turing.Undecided},
+		}
+		for _, tt := range tests {
+			outcome, err := r.Recognize(tt.word)
+			assert.Equal(t, tt.outcome, outcome, tt.word)
+			if tt.outcome == turing.Failed {
+				assert.True(t, errors.Is(err, turing.ErrNoSymbolOp), tt.word)
+			} else {
+				assert.NoError(t, err, tt.word)
+			}
+		}
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		t.Log("should accept on the accepting states and reject on other halting states")
+
+		r := evenZeros()
+		r.Accept = []turing.State{turing.RejectState}
+		outcome, _ := r.Recognize("000")
+		assert.Equal(t, turing.Accepted, outcome)
+		outcome, _ = r.Recognize("00")
+		assert.Equal(t, turing.Rejected, outcome)
+	})
+
+	t.Run("Symbols", func(t *testing.T) {
+		t.Log("should convert digits to the symbols the program uses")
+
+		ok := turing.State{"ok", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{ok, "1", turing.KEEP, turing.RIGHT, ok})
+		program.AddOp(turing.Op{ok, 2, turing.KEEP, turing.RIGHT, ok})
+		program.AddOp(turing.Op{ok, "a", turing.KEEP, turing.RIGHT, ok})
+		program.AddOp(turing.Op{ok, nil, turing.KEEP, turing.RIGHT, turing.AcceptState})
+		r := turing.Recognizer{Program: &program, Start: ok}
+
+		outcome, err := r.Recognize("12a")
+		assert.Equal(t, turing.Accepted, outcome)
+		assert.NoError(t, err)
+		outcome, err = r.RecognizeSymbols([]turing.Symbol{1})
+		assert.Equal(t, turing.Failed, outcome)
+		assert.EqualError(t, err, "no operation for state ok and symbol 1")
+		outcome, _ = r.Recognize("1_2")
+		assert.Equal(t, turing.Accepted, outcome)
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		t.Log("should return the words with unexpected outcomes")
+
+		r := evenZeros()
+		mismatches := r.Check([]string{"", "00", "0", "2"}, []string{"000", "00", "1"})
+		if assert.Len(t, mismatches, 4) {
+			assert.Equal(t, `word "0": expected accepted, got rejected`, mismatches[0].String())
+			assert.Equal(t, `word "2": expected accepted, got undecided`, mismatches[1].String())
+			assert.Equal(t, `word "00": expected rejected, got accepted`, mismatches[2].String())
+			assert.Equal(t, `word "1": expected rejected, got failed: no operation for state even and symbol 1`, mismatches[3].String())
+		}
+
+		palindrome := machines.Palindrome().Recognizer()
+		assert.Empty(t, palindrome.Check(
+			[]string{"", "0", "11", "010", "0110", "10101"},
+			[]string{"01", "10", "001", "0111", "10100"},
+		))
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Log("should describe the outcomes")
+
+		assert.Equal(t, "accepted", turing.Accepted.String())
+		assert.Equal(t, "rejected", turing.Rejected.String())
+		assert.Equal(t, "failed", turing.Failed.String())
+		assert.Equal(t, "undecided", turing.Undecided.String())
+		assert.Equal(t, "Outcome(9)", turing.Outcome(9).String())
+
+		var unset turing.Outcome
+		assert.Equal(t, turing.Undecided, unset)
+	})
+}
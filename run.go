@@ -0,0 +1,154 @@
+package turing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// StopReason tells why a run stopped.
+//
+// The zero StopReason is not a reason, so an unset RunResult does not look
+// like a halted run.
+type StopReason int
+
+const (
+	// Halted means the machine reached a halt state.
+	Halted StopReason = iota + 1
+	// BudgetExhausted means the machine executed the maximum number of steps
+	// without halting.
+	BudgetExhausted
+	// Cancelled means the run context was cancelled or its deadline expired.
+	Cancelled
+	// NoOperation means there was no operation for the current state and the
+	// symbol under the head.
+	NoOperation
+	// Looping means the machine loop detector found a cycle, the machine
+	// never halts.
+	Looping
+	// OutOfBounds means the head went outside of a bounded tape.
+	OutOfBounds
+	// TapeError means the tape failed to read or write the symbol under the
+	// head.
+	TapeError
+	// StepFailed means the step failed for any other reason.
+	StepFailed
+)
+
+var stopReasonNames = map[StopReason]string{
+	Halted:          "halted",
+	BudgetExhausted: "budget exhausted",
+	Cancelled:       "cancelled",
+	NoOperation:     "no operation",
+	Looping:         "looping",
+	OutOfBounds:     "out of bounds",
+	TapeError:       "tape error",
+	StepFailed:      "step failed",
+}
+
+// String returns the reason description.
+func (r StopReason) String() string {
+	if name, ok := stopReasonNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("StopReason(%d)", int(r))
+}
+
+// RunResult is the outcome of a limited run.
+type RunResult struct {
+	// Steps is the number of steps executed during the run.
+	Steps int
+	// Reason is why the run stopped.
+	Reason StopReason
+	// Cycle is the cycle found when Reason is Looping.
+	Cycle Cycle
+}
+
+// stepper is a machine that can be run step by step.
+type stepper interface {
+	Step() error
+	halted() bool
+}
+
+// looper is a stepper with loop detection.
+type looper interface {
+	// loopError returns a *LoopError when the machine is on a cycle.
+	loopError() error
+}
+
+// RunContext executes the current program like Run, but also stops when ctx
+// is done or after maxSteps steps. A maxSteps smaller than 1 means there is
+// no step limit.
+//
+// The error is ctx.Err() when the run is cancelled and the Step error when
+// the step fails, because there is no operation to execute, the head goes
+// out of the tape bounds or the tape fails, otherwise it is nil. The machine
+// stays on the configuration where it stopped, so calling RunContext again resumes
+// the run. With a LoopDetector, the run also stops with the Looping reason
+// when the machine is on a cycle.
+func (m *Machine) RunContext(ctx context.Context, maxSteps int) (RunResult, error) {
+	return runContext(ctx, maxSteps, m)
+}
+
+func (m *Machine) halted() bool {
+	return m.State.Halt
+}
+
+func (m *Machine) loopError() error {
+	if m.LoopDetector == nil {
+		return nil
+	}
+	if cycle := m.LoopDetector.take(); cycle != nil {
+		return &LoopError{Cycle: *cycle}
+	}
+	return nil
+}
+
+func runContext(ctx context.Context, maxSteps int, s stepper) (RunResult, error) {
+	result := RunResult{}
+	for !s.halted() {
+		if maxSteps > 0 && result.Steps >= maxSteps {
+			result.Reason = BudgetExhausted
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			result.Reason = Cancelled
+			return result, ctx.Err()
+		default:
+		}
+		if err := s.Step(); err != nil {
+			result.Reason = stepStopReason(err)
+			return result, err
+		}
+		result.Steps++
+		if l, ok := s.(looper); ok {
+			if err := l.loopError(); err != nil {
+				result.Reason = stepStopReason(err)
+				var loopErr *LoopError
+				if errors.As(err, &loopErr) {
+					result.Cycle = loopErr.Cycle
+				}
+				return result, nil
+			}
+		}
+	}
+	result.Reason = Halted
+	return result, nil
+}
+
+// stepStopReason returns the reason to stop a run on the step or loop
+// error.
+func stepStopReason(err error) StopReason {
+	switch {
+	case errors.Is(err, ErrNoStateOp), errors.Is(err, ErrNoSymbolOp):
+		return NoOperation
+	case errors.Is(err, ErrOutOfBounds):
+		return OutOfBounds
+	case errors.Is(err, ErrLooping):
+		return Looping
+	case errors.Is(err, ErrTapeRead), errors.Is(err, ErrTapeWrite):
+		return TapeError
+	}
+	return StepFailed
+}
@@ -0,0 +1,180 @@
+package turing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLoopMachine creates a machine that walks right forever.
+func newLoopMachine() *turing.Machine {
+	walk := turing.State{"walk", false}
+
+	program := turing.Program{}
+	program.AddOp(turing.Op{walk, turing.ANY, turing.KEEP, turing.RIGHT, walk})
+
+	head := turing.Head{}
+	head.Attach(turing.NewInfiniteTape(), 0)
+
+	return &turing.Machine{Head: &head, Program: &program, State: walk}
+}
+
+func TestRunContext(t *testing.T) {
+	t.Run("Halted", func(t *testing.T) {
+		t.Log("should run until a halt state")
+
+		zeroAll := turing.State{"zero all", false}
+		halt := turing.State{"halt", true}
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 1, 0, 1)
+
+		head := turing.Head{}
+		head.Attach(tape, 0)
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{zeroAll, turing.ANY, 0, turing.RIGHT, zeroAll})
+		program.AddOp(turing.Op{zeroAll, nil, nil, turing.STAY, halt})
+
+		machine := turing.Machine{Head: &head, Program: &program, State: zeroAll}
+
+		result, err := machine.RunContext(context.Background(), 100)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.RunResult{Steps: 4, Reason: turing.Halted}, result)
+			assert.Equal(t, halt, machine.State)
+		}
+	})
+
+	t.Run("AlreadyHalted", func(t *testing.T) {
+		t.Log("should not execute steps on a halted machine")
+
+		machine := newLoopMachine()
+		machine.State = turing.State{"halt", true}
+
+		result, err := machine.RunContext(context.Background(), 10)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.RunResult{Steps: 0, Reason: turing.Halted}, result)
+		}
+	})
+
+	t.Run("BudgetExhausted", func(t *testing.T) {
+		t.Log("should stop after the maximum number of steps and resume")
+
+		machine := newLoopMachine()
+
+		result, err := machine.RunContext(context.Background(), 10)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.RunResult{Steps: 10, Reason: turing.BudgetExhausted}, result)
+			assert.Equal(t, 10, machine.Head.Pos())
+		}
+
+		result, err = machine.RunContext(context.Background(), 5)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.RunResult{Steps: 5, Reason: turing.BudgetExhausted}, result)
+			assert.Equal(t, 15, machine.Head.Pos())
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Log("should stop when the context is cancelled")
+
+		machine := newLoopMachine()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result, err := machine.RunContext(ctx, 0)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, turing.RunResult{Steps: 0, Reason: turing.Cancelled}, result)
+	})
+
+	t.Run("NoOperation", func(t *testing.T) {
+		t.Log("should stop when there is no operation and keep the configuration")
+
+		state := turing.State{"potato", false}
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 0, 0, 5)
+
+		head := turing.Head{}
+		head.Attach(tape, 0)
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, 0, turing.KEEP, turing.RIGHT, state})
+
+		machine := turing.Machine{Head: &head, Program: &program, State: state}
+
+		result, err := machine.RunContext(context.Background(), 0)
+		assert.EqualError(t, err, "no operation for state potato and symbol 5")
+		assert.Equal(t, turing.RunResult{Steps: 2, Reason: turing.NoOperation}, result)
+		assert.Equal(t, 2, head.Pos())
+		assert.Equal(t, state, machine.State)
+	})
+
+	t.Run("TapeError", func(t *testing.T) {
+		t.Log("should stop with the tape error reason when the tape fails")
+
+		state := turing.State{"state", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, 0, 1, turing.RIGHT, state})
+
+		for _, tape := range []turing.Tape{
+			failTape{mapTape: mapTape{0: 0, 1: 0}, failRead: 1, failWrite: -1},
+			failTape{mapTape: mapTape{0: 0, 1: 0}, failRead: -1, failWrite: 1},
+		} {
+			head := turing.Head{}
+			head.Attach(tape, 0)
+			machine := turing.Machine{Head: &head, Program: &program, State: state}
+
+			result, err := machine.RunContext(context.Background(), 0)
+			assert.Error(t, err)
+			assert.Equal(t, turing.RunResult{Steps: 1, Reason: turing.TapeError}, result)
+		}
+	})
+
+	t.Run("Looping", func(t *testing.T) {
+		t.Log("should stop with the looping reason and the cycle found")
+
+		machine := newLoopMachine()
+		machine.LoopDetector = turing.NewLoopDetector()
+
+		result, err := machine.RunContext(context.Background(), 100)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.Looping, result.Reason)
+			assert.Equal(t, turing.Cycle{Start: 1, Period: 1, Translation: 1}, result.Cycle)
+		}
+	})
+
+	t.Run("StepFailed", func(t *testing.T) {
+		t.Log("should stop with the step failed reason on errors without a kind")
+
+		state := turing.State{"state", false}
+		program := turing.MultiProgram{}
+		program.AddOp(turing.MultiOp{state, []turing.Symbol{turing.ANY, turing.ANY}, []turing.Symbol{1, 1}, []string{turing.RIGHT, turing.RIGHT}, state})
+
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+		machine := turing.MultiMachine{Heads: []*turing.Head{&head}, Program: &program, State: state}
+
+		result, err := machine.RunContext(context.Background(), 0)
+		assert.EqualError(t, err, "machine has 1 heads, program expects 2 tapes")
+		assert.Equal(t, turing.RunResult{Steps: 0, Reason: turing.StepFailed}, result)
+	})
+
+	t.Run("StopReasonString", func(t *testing.T) {
+		t.Log("should describe the stop reasons")
+
+		assert.Equal(t, "halted", turing.Halted.String())
+		assert.Equal(t, "budget exhausted", turing.BudgetExhausted.String())
+		assert.Equal(t, "cancelled", turing.Cancelled.String())
+		assert.Equal(t, "no operation", turing.NoOperation.String())
+		assert.Equal(t, "looping", turing.Looping.String())
+		assert.Equal(t, "out of bounds", turing.OutOfBounds.String())
+		assert.Equal(t, "tape error", turing.TapeError.String())
+		assert.Equal(t, "step failed", turing.StepFailed.String())
+		assert.Equal(t, "StopReason(0)", turing.StopReason(0).String())
+		assert.Equal(t, "StopReason(42)", turing.StopReason(42).String())
+	})
+}
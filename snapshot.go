@@ -0,0 +1,295 @@
+package turing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Snapshot is the full configuration of a machine: its state, the number
+// of executed steps, the head and the tape. It does not have the program.
+type Snapshot struct {
+	// State is the machine state.
+	State State
+	// Steps is the number of steps the machine executed.
+	Steps int
+	// Pos is the head position.
+	Pos int
+	// MinPos is the smallest position the head was on.
+	MinPos int
+	// MaxPos is the biggest position the head was on.
+	MaxPos int
+	// Tape is the tape contents.
+	Tape TapeSnapshot
+}
+
+// TapeSnapshot is the contents of a tape, in the tape own layout.
+type TapeSnapshot struct {
+	// Kind is the tape kind, "infinite" for tapes created by
+	// NewInfiniteTape, "sparse" for tapes created by NewSparseTape,
+	// "bounded" for tapes created by NewBoundedTape and "left bounded" for
+	// tapes created by NewLeftBoundedTape.
+	Kind string
+	// Shift is the number of cells before position 0 on Cells.
+	Shift int
+	// Cells are the tape cells, from position -Shift on.
+	Cells []Symbol
+	// First is the first position of bounded and left bounded tapes.
+	First int
+	// Last is the last position of bounded tapes.
+	Last int
+	// LeftMarker and RightMarker are the end markers of bounded tapes.
+	LeftMarker  Symbol
+	RightMarker Symbol
+	// Stay tells if the head stays on the first position of left bounded
+	// tapes when it moves left of it.
+	Stay bool
+}
+
+const (
+	// infiniteTapeKind is the snapshot kind of infinite tapes.
+	infiniteTapeKind = "infinite"
+	// sparseTapeKind is the snapshot kind of sparse tapes.
+	sparseTapeKind = "sparse"
+	// boundedTapeKind is the snapshot kind of bounded tapes.
+	boundedTapeKind = "bounded"
+	// leftBoundedTapeKind is the snapshot kind of left bounded tapes.
+	leftBoundedTapeKind = "left bounded"
+)
+
+// snapshotTape is a tape that can be copied to a snapshot.
+type snapshotTape interface {
+	snapshot() TapeSnapshot
+}
+
+func (t *infiniteTape) snapshot() TapeSnapshot {
+	cells, shift := t.cells()
+	return TapeSnapshot{Kind: infiniteTapeKind, Shift: shift, Cells: cells}
+}
+
+// snapshot copies the cells from the first to the last non blank symbol.
+func (t *sparseTape) snapshot() TapeSnapshot {
+	min, max, nonBlank := t.extent()
+	if !nonBlank {
+		return TapeSnapshot{Kind: sparseTapeKind, Cells: []Symbol{}}
+	}
+	cells := make([]Symbol, max-min+1)
+	for i := range cells {
+		cells[i], _ = t.Get(min + i)
+	}
+	return TapeSnapshot{Kind: sparseTapeKind, Shift: -min, Cells: cells}
+}
+
+func (t *boundedTape) snapshot() TapeSnapshot {
+	cells := make([]Symbol, len(t.cells))
+	copy(cells, t.cells)
+	return TapeSnapshot{
+		Kind:        boundedTapeKind,
+		Shift:       -t.first,
+		Cells:       cells,
+		First:       t.first,
+		Last:        t.first + len(t.cells) - 1,
+		LeftMarker:  t.leftMarker,
+		RightMarker: t.rightMarker,
+	}
+}
+
+func (t *leftBoundedTape) snapshot() TapeSnapshot {
+	cells, shift := t.cells.cells()
+	return TapeSnapshot{Kind: leftBoundedTapeKind, Shift: shift - t.first, Cells: cells, First: t.first, Stay: t.stay}
+}
+
+// restoreTape creates a tape from its snapshot.
+func restoreTape(s TapeSnapshot) (Tape, error) {
+	switch s.Kind {
+	case infiniteTapeKind:
+		tape := NewInfiniteTape()
+		tape.Set(-s.Shift, s.Cells...)
+		return tape, nil
+	case sparseTapeKind:
+		tape := NewSparseTape()
+		tape.Set(-s.Shift, s.Cells...)
+		return tape, nil
+	case boundedTapeKind:
+		tape := NewBoundedTape(s.First, s.Last, s.LeftMarker, s.RightMarker)
+		if err := tape.Set(-s.Shift, s.Cells...); err != nil {
+			return nil, err
+		}
+		return tape, nil
+	case leftBoundedTapeKind:
+		tape := NewLeftBoundedTape(s.First, s.Stay)
+		if err := tape.Set(-s.Shift, s.Cells...); err != nil {
+			return nil, err
+		}
+		return tape, nil
+	}
+	return nil, fmt.Errorf("unknown tape kind %q", s.Kind)
+}
+
+// Snapshot copies the machine configuration. The tape must be created by
+// this package.
+func (m *Machine) Snapshot() (Snapshot, error) {
+	tape, ok := m.Head.tape.(snapshotTape)
+	if !ok {
+		return Snapshot{}, fmt.Errorf("tape %T does not support snapshots", m.Head.tape)
+	}
+	return Snapshot{
+		State:  m.State,
+		Steps:  m.steps,
+		Pos:    m.Head.pos,
+		MinPos: m.Head.minPos,
+		MaxPos: m.Head.maxPos,
+		Tape:   tape.snapshot(),
+	}, nil
+}
+
+// Restore sets the machine configuration to the snapshot, with the head
+// attached to a new tape with the snapshot contents. The program is not
+// changed, a machine without head gets a new one, and the LoopDetector
+// forgets the configurations seen before.
+func (m *Machine) Restore(s Snapshot) error {
+	tape, err := restoreTape(s.Tape)
+	if err != nil {
+		return err
+	}
+	if m.Head == nil {
+		m.Head = &Head{}
+	}
+	*m.Head = Head{tape: tape, pos: s.Pos, minPos: s.MinPos, maxPos: s.MaxPos}
+	m.State = s.State
+	m.steps = s.Steps
+	if m.LoopDetector != nil {
+		m.LoopDetector.Reset()
+	}
+	return nil
+}
+
+type jsonCheckpoint struct {
+	State  jsonState        `json:"state"`
+	Steps  int              `json:"steps"`
+	Pos    int              `json:"pos"`
+	MinPos int              `json:"minPos"`
+	MaxPos int              `json:"maxPos"`
+	Tape   jsonTapeSnapshot `json:"tape"`
+}
+
+type jsonTapeSnapshot struct {
+	Kind        string            `json:"kind"`
+	Shift       int               `json:"shift"`
+	Cells       []json.RawMessage `json:"cells"`
+	First       int               `json:"first,omitempty"`
+	Last        int               `json:"last,omitempty"`
+	LeftMarker  json.RawMessage   `json:"leftMarker,omitempty"`
+	RightMarker json.RawMessage   `json:"rightMarker,omitempty"`
+	Stay        bool              `json:"stay,omitempty"`
+}
+
+// WriteCheckpoint writes the snapshot as a JSON checkpoint:
+//
+//	{
+//	  "state": {"name": "zero all"},
+//	  "steps": 2,
+//	  "pos": 2, "minPos": 0, "maxPos": 2,
+//	  "tape": {"kind": "infinite", "shift": 0, "cells": [0, 0, 1]}
+//	}
+//
+// Symbols are written like on JSON definitions: null for the blank symbol,
+// integers or strings.
+func WriteCheckpoint(w io.Writer, s Snapshot) error {
+	jc := jsonCheckpoint{
+		State:  jsonState{Name: s.State.Name, Halt: s.State.Halt},
+		Steps:  s.Steps,
+		Pos:    s.Pos,
+		MinPos: s.MinPos,
+		MaxPos: s.MaxPos,
+		Tape: jsonTapeSnapshot{
+			Kind:  s.Tape.Kind,
+			Shift: s.Tape.Shift,
+			Cells: make([]json.RawMessage, len(s.Tape.Cells)),
+			First: s.Tape.First,
+			Last:  s.Tape.Last,
+			Stay:  s.Tape.Stay,
+		},
+	}
+	for _, marker := range []struct {
+		name   string
+		symbol Symbol
+		raw    *json.RawMessage
+	}{
+		{"left marker", s.Tape.LeftMarker, &jc.Tape.LeftMarker},
+		{"right marker", s.Tape.RightMarker, &jc.Tape.RightMarker},
+	} {
+		if marker.symbol == nil {
+			continue
+		}
+		raw, err := encodeJSONSymbol(marker.symbol, "", nil)
+		if err != nil {
+			return fmt.Errorf("tape %s: %s", marker.name, err.Error())
+		}
+		*marker.raw = raw
+	}
+	for i, cell := range s.Tape.Cells {
+		raw, err := encodeJSONSymbol(cell, "", nil)
+		if err != nil {
+			return fmt.Errorf("tape cell %d: %s", i-s.Tape.Shift, err.Error())
+		}
+		jc.Tape.Cells[i] = raw
+	}
+
+	enc := json.NewEncoder(w)
+	return enc.Encode(jc)
+}
+
+// ReadCheckpoint reads a snapshot written by WriteCheckpoint.
+func ReadCheckpoint(r io.Reader) (Snapshot, error) {
+	var jc jsonCheckpoint
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&jc); err != nil {
+		return Snapshot{}, fmt.Errorf("invalid checkpoint: %s", err.Error())
+	}
+
+	s := Snapshot{
+		State:  State{Name: jc.State.Name, Halt: jc.State.Halt},
+		Steps:  jc.Steps,
+		Pos:    jc.Pos,
+		MinPos: jc.MinPos,
+		MaxPos: jc.MaxPos,
+		Tape: TapeSnapshot{
+			Kind:  jc.Tape.Kind,
+			Shift: jc.Tape.Shift,
+			Cells: make([]Symbol, len(jc.Tape.Cells)),
+			First: jc.Tape.First,
+			Last:  jc.Tape.Last,
+			Stay:  jc.Tape.Stay,
+		},
+	}
+	for _, marker := range []struct {
+		name   string
+		raw    json.RawMessage
+		symbol *Symbol
+	}{
+		{"left marker", jc.Tape.LeftMarker, &s.Tape.LeftMarker},
+		{"right marker", jc.Tape.RightMarker, &s.Tape.RightMarker},
+	} {
+		if marker.raw == nil {
+			continue
+		}
+		symbol, err := decodeJSONSymbol(marker.raw, "", nil)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("tape %s: %s", marker.name, err.Error())
+		}
+		*marker.symbol = symbol
+	}
+	if s.MinPos > s.Pos || s.Pos > s.MaxPos {
+		return Snapshot{}, fmt.Errorf("head position %d is not between %d and %d", s.Pos, s.MinPos, s.MaxPos)
+	}
+	for i, raw := range jc.Tape.Cells {
+		cell, err := decodeJSONSymbol(raw, "", nil)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("tape cell %d: %s", i-s.Tape.Shift, err.Error())
+		}
+		s.Tape.Cells[i] = cell
+	}
+	return s, nil
+}
@@ -0,0 +1,219 @@
+package turing_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+// mapTape is a tape implemented outside the package.
+type mapTape map[int]turing.Symbol
+
+func (t mapTape) Get(pos int) (turing.Symbol, error) {
+	return t[pos], nil
+}
+
+func (t mapTape) Set(pos int, symbols ...turing.Symbol) error {
+	for i, s := range symbols {
+		t[pos+i] = s
+	}
+	return nil
+}
+
+func TestSnapshot(t *testing.T) {
+	createMachine := func() *turing.Machine {
+		start, program := createSeparate01()
+		def := turing.Definition{Program: program, Start: start, Tape: []turing.Symbol{0, 1, 0, 1, 1, 0, 1}, TapeOffset: -3, HeadPos: -3}
+		return def.NewMachine()
+	}
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Log("should resume a run from a checkpoint with identical results")
+
+		machine := createMachine()
+		for i := 0; i < 10; i++ {
+			machine.Step()
+		}
+		snapshot, err := machine.Snapshot()
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 3, snapshot.Tape.Shift)
+
+		buff := bytes.Buffer{}
+		if !assert.NoError(t, turing.WriteCheckpoint(&buff, snapshot)) {
+			return
+		}
+		read, err := turing.ReadCheckpoint(&buff)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, snapshot, read)
+
+		resumed := turing.Machine{Program: machine.Program}
+		if !assert.NoError(t, resumed.Restore(read)) {
+			return
+		}
+		assert.Equal(t, 10, resumed.Steps())
+
+		if assert.NoError(t, machine.Run()) && assert.NoError(t, resumed.Run()) {
+			expected, _ := machine.Snapshot()
+			actual, _ := resumed.Snapshot()
+			assert.Equal(t, expected, actual)
+			assert.Equal(t, machine.Head.PrintTape(-5, 5), resumed.Head.PrintTape(-5, 5))
+		}
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		t.Log("should not share the tape between the snapshot and the machine")
+
+		machine := createMachine()
+		snapshot, err := machine.Snapshot()
+		if !assert.NoError(t, err) {
+			return
+		}
+		cells := append([]turing.Symbol{}, snapshot.Tape.Cells...)
+
+		assert.NoError(t, machine.Run())
+		assert.Equal(t, cells, snapshot.Tape.Cells)
+
+		head := machine.Head
+		if assert.NoError(t, machine.Restore(snapshot)) {
+			assert.True(t, head == machine.Head, "should keep the machine head")
+			assert.Equal(t, "[-3: 0]\n -2: 1\n -1: 0\n 0: 1\n 1: 1\n 2: 0\n 3: 1\n", machine.Head.PrintTape(-3, 3))
+			assert.Equal(t, 0, machine.Steps())
+
+			machine.Step()
+			assert.Equal(t, cells, snapshot.Tape.Cells)
+		}
+	})
+
+	t.Run("Checkpoint", func(t *testing.T) {
+		t.Log("should write the checkpoint as JSON")
+
+		state := turing.State{"state", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, turing.ANY, "a", turing.LEFT, state})
+
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+		machine := turing.Machine{Head: &head, Program: &program, State: state}
+		machine.Step()
+		machine.Step()
+
+		snapshot, err := machine.Snapshot()
+		if !assert.NoError(t, err) {
+			return
+		}
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.WriteCheckpoint(&builder, snapshot)) {
+			assert.Equal(t,
+				`{"state":{"name":"state"},"steps":2,"pos":-2,"minPos":-2,"maxPos":0,"tape":{"kind":"infinite","shift":1,"cells":["a","a"]}}`+"\n",
+				builder.String())
+		}
+	})
+
+	t.Run("BoundedTapes", func(t *testing.T) {
+		t.Log("should resume runs on bounded tapes from a checkpoint")
+
+		invert := turing.State{"invert", false}
+		halt := turing.State{"halt", true}
+		program := turing.Program{}
+		program.AddOp(turing.Op{invert, 0, 1, turing.RIGHT, invert})
+		program.AddOp(turing.Op{invert, 1, 0, turing.RIGHT, invert})
+		program.AddOp(turing.Op{invert, ">", turing.KEEP, turing.STAY, halt})
+		program.AddOp(turing.Op{invert, nil, turing.KEEP, turing.STAY, halt})
+
+		bounded := []struct {
+			name string
+			tape turing.Tape
+			pos  int
+		}{
+			{"Bounded", turing.NewBoundedTape(0, 3, "<", ">"), 0},
+			{"LeftBounded", turing.NewLeftBoundedTape(-2, true), -2},
+		}
+		for _, tt := range bounded {
+			tt.tape.Set(tt.pos, 0, 1, 1, 0)
+			head := turing.Head{}
+			head.Attach(tt.tape, tt.pos)
+			machine := turing.Machine{Head: &head, Program: &program, State: invert}
+			machine.Step()
+			machine.Step()
+
+			snapshot, err := machine.Snapshot()
+			if !assert.NoError(t, err, tt.name) {
+				continue
+			}
+			buff := bytes.Buffer{}
+			if !assert.NoError(t, turing.WriteCheckpoint(&buff, snapshot), tt.name) {
+				continue
+			}
+			read, err := turing.ReadCheckpoint(&buff)
+			if !assert.NoError(t, err, tt.name) {
+				continue
+			}
+			assert.Equal(t, snapshot, read, tt.name)
+
+			resumed := turing.Machine{Program: &program}
+			if !assert.NoError(t, resumed.Restore(read), tt.name) {
+				continue
+			}
+			if assert.NoError(t, machine.Run(), tt.name) && assert.NoError(t, resumed.Run(), tt.name) {
+				expected, _ := machine.Snapshot()
+				actual, _ := resumed.Snapshot()
+				assert.Equal(t, expected, actual, tt.name)
+				assert.Equal(t, machine.Head.PrintTape(tt.pos-1, tt.pos+4), resumed.Head.PrintTape(tt.pos-1, tt.pos+4), tt.name)
+			}
+		}
+	})
+
+	t.Run("LoopDetector", func(t *testing.T) {
+		t.Log("should reset the loop detector on restore")
+
+		machine := createMachine()
+		machine.LoopDetector = turing.NewLoopDetector()
+		start, err := machine.Snapshot()
+		if !assert.NoError(t, err) {
+			return
+		}
+		machine.Step()
+
+		if !assert.NoError(t, machine.Restore(start)) {
+			return
+		}
+		assert.NoError(t, machine.Run())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Log("should report tapes and checkpoints that can't be used")
+
+		head := turing.Head{}
+		head.Attach(mapTape{}, 0)
+		machine := turing.Machine{Head: &head}
+		_, err := machine.Snapshot()
+		assert.EqualError(t, err, "tape turing_test.mapTape does not support snapshots")
+
+		err = machine.Restore(turing.Snapshot{Tape: turing.TapeSnapshot{Kind: "paper"}})
+		assert.EqualError(t, err, `unknown tape kind "paper"`)
+
+		snapshot := turing.Snapshot{Tape: turing.TapeSnapshot{Kind: "infinite", Shift: 1, Cells: []turing.Symbol{nil, 1.5}}}
+		assert.EqualError(t, turing.WriteCheckpoint(&strings.Builder{}, snapshot), "tape cell 0: unsupported symbol type float64")
+
+		tests := []struct {
+			json string
+			err  string
+		}{
+			{`{"steps": 1`, "invalid checkpoint: unexpected EOF"},
+			{`{"head": 1}`, `invalid checkpoint: json: unknown field "head"`},
+			{`{"pos": 3, "minPos": 0, "maxPos": 2}`, "head position 3 is not between 0 and 2"},
+			{`{"tape": {"kind": "infinite", "shift": 1, "cells": [null, 1.5]}}`, "tape cell 0: symbol 1.5 is not an int"},
+		}
+		for _, tt := range tests {
+			_, err := turing.ReadCheckpoint(strings.NewReader(tt.json))
+			assert.EqualError(t, err, tt.err, tt.json)
+		}
+	})
+}
@@ -0,0 +1,369 @@
+package turing
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+)
+
+// SpaceTime is an Observer that records a run to draw its space-time
+// diagram, with one row per step and one column per tape cell the head
+// visited.
+//
+// The first row is the tape before the first step, and each row after it is
+// the tape after one step. Each symbol has its own colour, blank cells are
+// white, the cell under the head is highlighted and a strip on the left has
+// the colour of the machine state.
+type SpaceTime struct {
+	head    *Head
+	first   int
+	state   State
+	pos     int
+	minPos  int
+	maxPos  int
+	initial map[int]Symbol
+	steps   []spaceTimeStep
+}
+
+// spaceTimeStep is the tape change made by one step.
+type spaceTimeStep struct {
+	pos     int
+	written Symbol
+	to      int
+	state   State
+}
+
+// SpaceTimeOptions configures how a space-time diagram is drawn.
+type SpaceTimeOptions struct {
+	// CellSize is the side of each cell, in pixels. It is 4 when not set.
+	CellSize int
+	// MaxRows is the maximum number of rows drawn. Longer runs are
+	// downsampled to evenly spaced steps, always keeping the first and the
+	// last one. It is 1000 when not set.
+	MaxRows int
+	// MaxColumns is the maximum number of cell columns drawn. Wider tapes
+	// are downsampled to evenly spaced cells, always keeping the first and
+	// the last one. It is 1000 when not set.
+	MaxColumns int
+}
+
+const (
+	defaultSpaceTimeCellSize   = 4
+	defaultSpaceTimeMaxRows    = 1000
+	defaultSpaceTimeMaxColumns = 1000
+)
+
+// spaceTimePalette are the colours of the symbols and states.
+var spaceTimePalette = []color.RGBA{
+	{0x1f, 0x77, 0xb4, 0xff},
+	{0xff, 0x7f, 0x0e, 0xff},
+	{0x2c, 0xa0, 0x2c, 0xff},
+	{0xd6, 0x27, 0x28, 0xff},
+	{0x94, 0x67, 0xbd, 0xff},
+	{0x8c, 0x56, 0x4b, 0xff},
+	{0xe3, 0x77, 0xc2, 0xff},
+	{0x7f, 0x7f, 0x7f, 0xff},
+	{0xbc, 0xbd, 0x22, 0xff},
+	{0x17, 0xbe, 0xcf, 0xff},
+}
+
+var (
+	spaceTimeBlank = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	spaceTimeHead  = color.RGBA{0x00, 0x00, 0x00, 0xff}
+)
+
+// NewSpaceTime creates a space-time recorder for the machine, starting on
+// its current state and head position. It must be set as the machine
+// observer, or be one of its observers.
+//
+// The recorder keeps the symbol of each cell when the head first reaches
+// it, so the diagram does not read the tape, which can change after the
+// run.
+func NewSpaceTime(m *Machine) *SpaceTime {
+	pos := m.Head.Pos()
+	symbol, _ := m.Head.Read()
+	return &SpaceTime{
+		head:    m.Head,
+		first:   m.steps,
+		state:   m.State,
+		pos:     pos,
+		minPos:  pos,
+		maxPos:  pos,
+		initial: map[int]Symbol{pos: symbol},
+	}
+}
+
+// Observe records a step.
+func (s *SpaceTime) Observe(e StepEvent) {
+	if _, seen := s.initial[e.From]; !seen {
+		s.initial[e.From] = e.Read
+	}
+	if _, seen := s.initial[e.To]; !seen {
+		s.initial[e.To], _ = s.head.tape.Get(e.To)
+	}
+	if e.To < s.minPos {
+		s.minPos = e.To
+	}
+	if e.To > s.maxPos {
+		s.maxPos = e.To
+	}
+	s.steps = append(s.steps, spaceTimeStep{pos: e.From, written: e.Written, to: e.To, state: e.NextState})
+}
+
+// Rewind forgets the steps after step, for a Timeline that takes the machine
+// back.
+func (s *SpaceTime) Rewind(step int) {
+	n := step - s.first
+	if n < 0 {
+		n = 0
+	}
+	if n >= len(s.steps) {
+		return
+	}
+	s.steps = s.steps[:n]
+	s.minPos, s.maxPos = s.pos, s.pos
+	for _, st := range s.steps {
+		if st.to < s.minPos {
+			s.minPos = st.to
+		}
+		if st.to > s.maxPos {
+			s.maxPos = st.to
+		}
+	}
+}
+
+// Steps returns the number of recorded steps.
+func (s *SpaceTime) Steps() int {
+	return len(s.steps)
+}
+
+// spaceTimeRow is one drawn row of the diagram.
+type spaceTimeRow struct {
+	step  int
+	cells []Symbol
+	head  int
+	state State
+}
+
+// rows replays the recorded steps and returns the rows to draw, with at
+// most maxColumns cells each.
+func (s *SpaceTime) rows(maxRows, maxColumns int) []spaceTimeRow {
+	cells := make([]Symbol, s.maxPos-s.minPos+1)
+	for i := range cells {
+		cells[i] = s.initial[s.minPos+i]
+	}
+	columns := spaceTimeSample(len(cells), maxColumns)
+	rowSteps := spaceTimeSample(len(s.steps)+1, maxRows)
+
+	rows := make([]spaceTimeRow, 0, len(rowSteps))
+	head, state := s.pos, s.state
+	next := 0
+	for step := 0; next < len(rowSteps); step++ {
+		if step > 0 {
+			change := s.steps[step-1]
+			cells[change.pos-s.minPos] = change.written
+			head, state = change.to, change.state
+		}
+		if rowSteps[next] != step {
+			continue
+		}
+		row := spaceTimeRow{step: step, cells: make([]Symbol, len(columns)), state: state}
+		for i, c := range columns {
+			row.cells[i] = cells[c]
+		}
+		row.head = spaceTimeColumn(head-s.minPos, len(cells), len(columns))
+		rows = append(rows, row)
+		next++
+	}
+	return rows
+}
+
+// spaceTimeSample returns at most max evenly spaced indexes of n items,
+// always keeping the first and the last one.
+func spaceTimeSample(n, max int) []int {
+	if max > n {
+		max = n
+	}
+	sampled := make([]int, max)
+	for i := range sampled {
+		sampled[i] = i
+		if max > 1 {
+			sampled[i] = i * (n - 1) / (max - 1)
+		}
+	}
+	return sampled
+}
+
+// spaceTimeColumn returns the sampled column nearest to the cell i of n
+// cells.
+func spaceTimeColumn(i, n, columns int) int {
+	if columns == n || n == 1 {
+		return i
+	}
+	return (i*(columns-1) + (n-1)/2) / (n - 1)
+}
+
+// spaceTimeColours assigns a colour to each symbol and state on the rows,
+// in their sorting order, and returns them sorted.
+func spaceTimeColours(rows []spaceTimeRow) ([]Symbol, map[Symbol]color.RGBA, []State, map[State]color.RGBA) {
+	symbolSet := make(map[Symbol]bool)
+	stateSet := make(map[State]bool)
+	var symbols []Symbol
+	var states []State
+	for _, row := range rows {
+		for _, symbol := range row.cells {
+			if !symbolSet[symbol] {
+				symbolSet[symbol] = true
+				symbols = append(symbols, symbol)
+			}
+		}
+		if !stateSet[row.state] {
+			stateSet[row.state] = true
+			states = append(states, row.state)
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return compareSymbols(symbols[i], symbols[j]) < 0
+	})
+	sortStates(states)
+
+	symbolColours := make(map[Symbol]color.RGBA, len(symbols))
+	next := 0
+	for _, symbol := range symbols {
+		if symbol == nil {
+			symbolColours[symbol] = spaceTimeBlank
+			continue
+		}
+		symbolColours[symbol] = spaceTimePalette[next%len(spaceTimePalette)]
+		next++
+	}
+	stateColours := make(map[State]color.RGBA, len(states))
+	for i, state := range states {
+		stateColours[state] = spaceTimePalette[i%len(spaceTimePalette)]
+	}
+	return symbols, symbolColours, states, stateColours
+}
+
+func (o SpaceTimeOptions) withDefaults() SpaceTimeOptions {
+	if o.CellSize < 1 {
+		o.CellSize = defaultSpaceTimeCellSize
+	}
+	if o.MaxRows < 1 {
+		o.MaxRows = defaultSpaceTimeMaxRows
+	}
+	if o.MaxColumns < 1 {
+		o.MaxColumns = defaultSpaceTimeMaxColumns
+	}
+	return o
+}
+
+// WritePNG draws the space-time diagram as a PNG image.
+//
+// The head cell is outlined in black, or filled in black when the cells are
+// smaller than 3 pixels.
+func (s *SpaceTime) WritePNG(w io.Writer, opts SpaceTimeOptions) error {
+	opts = opts.withDefaults()
+	rows := s.rows(opts.MaxRows, opts.MaxColumns)
+	_, symbolColours, _, stateColours := spaceTimeColours(rows)
+
+	size := opts.CellSize
+	strip := 2 * size
+	width := strip + len(rows[0].cells)*size
+	img := image.NewRGBA(image.Rect(0, 0, width, len(rows)*size))
+	fill := func(x, y, w, h int, c color.RGBA) {
+		draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
+	}
+
+	for r, row := range rows {
+		y := r * size
+		fill(0, y, strip, size, stateColours[row.state])
+		for i, symbol := range row.cells {
+			fill(strip+i*size, y, size, size, symbolColours[symbol])
+		}
+		x := strip + row.head*size
+		if size < 3 {
+			fill(x, y, size, size, spaceTimeHead)
+			continue
+		}
+		fill(x, y, size, 1, spaceTimeHead)
+		fill(x, y+size-1, size, 1, spaceTimeHead)
+		fill(x, y, 1, size, spaceTimeHead)
+		fill(x+size-1, y, 1, size, spaceTimeHead)
+	}
+
+	return png.Encode(w, img)
+}
+
+// WriteSVG draws the space-time diagram as an SVG image, with a legend of
+// the symbol and state colours below it.
+//
+// The head cell is outlined in black, and each row of the state strip has
+// the step number and state as its title.
+func (s *SpaceTime) WriteSVG(w io.Writer, opts SpaceTimeOptions) error {
+	opts = opts.withDefaults()
+	rows := s.rows(opts.MaxRows, opts.MaxColumns)
+	symbols, symbolColours, states, stateColours := spaceTimeColours(rows)
+
+	size := opts.CellSize
+	strip := 2 * size
+	width := strip + len(rows[0].cells)*size
+	height := len(rows) * size
+	const legendLine = 16
+	legendHeight := (len(symbols) + len(states)) * legendLine
+	if width < 160 {
+		width = 160
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" shape-rendering=\"crispEdges\">\n", width, height+legendHeight+legendLine/2)
+	fmt.Fprintf(bw, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", width, height+legendHeight+legendLine/2, svgColour(spaceTimeBlank))
+
+	for r, row := range rows {
+		y := r * size
+		fmt.Fprintf(bw, "<rect x=\"0\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"><title>step %d: %s</title></rect>\n",
+			y, strip, size, svgColour(stateColours[row.state]), row.step, html.EscapeString(row.state.String()))
+		for start := 0; start < len(row.cells); {
+			end := start + 1
+			for end < len(row.cells) && row.cells[end] == row.cells[start] {
+				end++
+			}
+			if row.cells[start] != nil {
+				fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+					strip+start*size, y, (end-start)*size, size, svgColour(symbolColours[row.cells[start]]))
+			}
+			start = end
+		}
+		fmt.Fprintf(bw, "<rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"none\" stroke=\"%s\" stroke-width=\"1\"/>\n",
+			float64(strip+row.head*size)+0.5, float64(y)+0.5, float64(size)-1, float64(size)-1, svgColour(spaceTimeHead))
+	}
+
+	y := height + legendLine/2
+	legend := func(c color.RGBA, label string) {
+		fmt.Fprintf(bw, "<rect x=\"0\" y=\"%d\" width=\"12\" height=\"12\" fill=\"%s\" stroke=\"%s\"/>\n", y+2, svgColour(c), svgColour(spaceTimeHead))
+		fmt.Fprintf(bw, "<text x=\"16\" y=\"%d\" font-family=\"monospace\" font-size=\"12\">%s</text>\n", y+12, html.EscapeString(label))
+		y += legendLine
+	}
+	for _, symbol := range symbols {
+		if symbol == nil {
+			legend(symbolColours[symbol], "blank")
+			continue
+		}
+		legend(symbolColours[symbol], fmt.Sprintf("symbol %v", symbol))
+	}
+	for _, state := range states {
+		legend(stateColours[state], fmt.Sprintf("state %v", state))
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+func svgColour(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
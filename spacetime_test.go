@@ -0,0 +1,186 @@
+package turing_test
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpaceTime(t *testing.T) {
+	state := turing.State{"state", false}
+	back := turing.State{"back", false}
+	halt := turing.State{"halt", true}
+
+	createMachine := func() *turing.Machine {
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, 1, 0, turing.RIGHT, state})
+		program.AddOp(turing.Op{state, nil, turing.KEEP, turing.LEFT, back})
+		program.AddOp(turing.Op{back, turing.ANY, turing.KEEP, turing.STAY, halt})
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 1)
+
+		head := turing.Head{}
+		head.Attach(tape, 0)
+
+		return &turing.Machine{Head: &head, Program: &program, State: state}
+	}
+
+	t.Run("PNG", func(t *testing.T) {
+		t.Log("should draw one row per step, with the state strip and the head cell")
+
+		machine := createMachine()
+		spaceTime := turing.NewSpaceTime(machine)
+		machine.Observer = spaceTime
+		if !assert.NoError(t, machine.Run()) {
+			return
+		}
+		assert.Equal(t, 3, spaceTime.Steps())
+
+		buff := bytes.Buffer{}
+		if !assert.NoError(t, spaceTime.WritePNG(&buff, turing.SpaceTimeOptions{CellSize: 1})) {
+			return
+		}
+		img, err := png.Decode(&buff)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// 2 pixels of state strip and 2 cells, for the initial tape and 3 steps.
+		assert.Equal(t, 4, img.Bounds().Dx())
+		assert.Equal(t, 4, img.Bounds().Dy())
+
+		black := color.RGBA{0, 0, 0, 0xff}
+		white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+		rgba := func(x, y int) color.RGBA {
+			return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+		}
+		assert.Equal(t, black, rgba(2, 0), "head on cell 0 before the first step")
+		assert.Equal(t, white, rgba(3, 0), "blank cell 1 before the first step")
+		assert.Equal(t, black, rgba(3, 1), "head on cell 1 after the first step")
+		assert.NotEqual(t, white, rgba(2, 1), "symbol 0 written on cell 0")
+		assert.NotEqual(t, rgba(0, 0), rgba(0, 2), "state strip changes from state to back")
+		assert.NotEqual(t, rgba(0, 2), rgba(0, 3), "state strip changes from back to halt")
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		t.Log("should draw the diagram with the state titles and a legend")
+
+		machine := createMachine()
+		spaceTime := turing.NewSpaceTime(machine)
+		machine.Observer = spaceTime
+		if !assert.NoError(t, machine.Run()) {
+			return
+		}
+
+		builder := strings.Builder{}
+		if !assert.NoError(t, spaceTime.WriteSVG(&builder, turing.SpaceTimeOptions{CellSize: 10})) {
+			return
+		}
+		svg := builder.String()
+		assert.True(t, strings.HasPrefix(svg, "<svg "), svg)
+		assert.True(t, strings.HasSuffix(svg, "</svg>\n"), svg)
+		assert.Contains(t, svg, "<title>step 0: state</title>")
+		assert.Contains(t, svg, "<title>step 3: [halt]</title>")
+		assert.Contains(t, svg, ">blank</text>")
+		assert.Contains(t, svg, ">symbol 0</text>")
+		assert.Contains(t, svg, ">symbol 1</text>")
+		assert.Contains(t, svg, ">state back</text>")
+		assert.Contains(t, svg, `<rect x="30.5" y="10.5" width="9" height="9" fill="none" stroke="#000000" stroke-width="1"/>`)
+	})
+
+	t.Run("Downsample", func(t *testing.T) {
+		t.Log("should downsample long runs to the maximum number of rows")
+
+		left := turing.State{"left", false}
+		right := turing.State{"right", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{left, turing.ANY, turing.KEEP, turing.RIGHT, right})
+		program.AddOp(turing.Op{right, turing.ANY, turing.KEEP, turing.LEFT, left})
+
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+		machine := turing.Machine{Head: &head, Program: &program, State: left}
+
+		spaceTime := turing.NewSpaceTime(&machine)
+		machine.Observer = spaceTime
+		result, err := machine.RunContext(context.Background(), 50000)
+		if !assert.NoError(t, err) || !assert.Equal(t, turing.BudgetExhausted, result.Reason) {
+			return
+		}
+
+		buff := bytes.Buffer{}
+		if !assert.NoError(t, spaceTime.WritePNG(&buff, turing.SpaceTimeOptions{CellSize: 2, MaxRows: 100})) {
+			return
+		}
+		img, err := png.Decode(&buff)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 8, img.Bounds().Dx())
+			assert.Equal(t, 200, img.Bounds().Dy())
+		}
+	})
+
+	t.Run("DownsampleColumns", func(t *testing.T) {
+		t.Log("should downsample wide tapes to the maximum number of columns")
+
+		walk := turing.State{"walk", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{walk, turing.ANY, 1, turing.RIGHT, walk})
+
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+		machine := turing.Machine{Head: &head, Program: &program, State: walk}
+
+		spaceTime := turing.NewSpaceTime(&machine)
+		machine.Observer = spaceTime
+		if _, err := machine.RunContext(context.Background(), 5000); !assert.NoError(t, err) {
+			return
+		}
+
+		buff := bytes.Buffer{}
+		if !assert.NoError(t, spaceTime.WritePNG(&buff, turing.SpaceTimeOptions{CellSize: 1, MaxRows: 50, MaxColumns: 100})) {
+			return
+		}
+		img, err := png.Decode(&buff)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 102, img.Bounds().Dx())
+			assert.Equal(t, 50, img.Bounds().Dy())
+			black := color.RGBA{0, 0, 0, 0xff}
+			assert.Equal(t, black, color.RGBAModel.Convert(img.At(101, 49)), "head on the last column after the last step")
+		}
+	})
+
+	t.Run("TapeChanged", func(t *testing.T) {
+		t.Log("should draw the recorded run after the tape changes")
+
+		walk := turing.State{"walk", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{walk, turing.ANY, 1, turing.RIGHT, walk})
+
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+		machine := turing.Machine{Head: &head, Program: &program, State: walk}
+
+		spaceTime := turing.NewSpaceTime(&machine)
+		machine.Observer = spaceTime
+		if _, err := machine.RunContext(context.Background(), 3); !assert.NoError(t, err) {
+			return
+		}
+
+		before := strings.Builder{}
+		if !assert.NoError(t, spaceTime.WriteSVG(&before, turing.SpaceTimeOptions{})) {
+			return
+		}
+		machine.Head.Write(7)
+		after := strings.Builder{}
+		if assert.NoError(t, spaceTime.WriteSVG(&after, turing.SpaceTimeOptions{})) {
+			assert.Equal(t, before.String(), after.String())
+		}
+	})
+}
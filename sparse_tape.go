@@ -0,0 +1,89 @@
+package turing
+
+// sparseTapePageBits is the log2 of the number of cells on a sparse tape page.
+const sparseTapePageBits = 6
+
+const sparseTapePageSize = 1 << sparseTapePageBits
+
+// sparseTapePage is a block of contiguous cells of a sparse tape.
+type sparseTapePage struct {
+	cells [sparseTapePageSize]Symbol
+	// used is the number of non blank cells on the page
+	used int
+}
+
+// sparseTape is a turing machine tape that only keeps the pages with non
+// blank symbols.
+type sparseTape struct {
+	pages map[int]*sparseTapePage
+}
+
+// NewSparseTape creates a new sparse tape. Like an infinite tape it is
+// 'infinite' in both directions and initialized with the blank symbol (nil),
+// but it stores the symbols on fixed size pages indexed by position, so
+// writing far from the other symbols does not copy or allocate the cells
+// between them. Pages are released when all their cells become blank.
+func NewSparseTape() Tape {
+	return &sparseTape{pages: make(map[int]*sparseTapePage)}
+}
+
+// sparseTapeCell returns the page index and the cell index on the page of
+// the position.
+func sparseTapeCell(pos int) (page, cell int) {
+	return pos >> sparseTapePageBits, pos & (sparseTapePageSize - 1)
+}
+
+func (t *sparseTape) Get(pos int) (Symbol, error) {
+	page, cell := sparseTapeCell(pos)
+	p, ok := t.pages[page]
+	if !ok {
+		return nil, nil
+	}
+	return p.cells[cell], nil
+}
+
+func (t *sparseTape) Set(pos int, symbols ...Symbol) error {
+	for i, s := range symbols {
+		page, cell := sparseTapeCell(pos + i)
+		p, ok := t.pages[page]
+		if !ok {
+			if s == nil {
+				continue
+			}
+			p = &sparseTapePage{}
+			t.pages[page] = p
+		}
+
+		switch {
+		case p.cells[cell] == nil && s != nil:
+			p.used++
+		case p.cells[cell] != nil && s == nil:
+			p.used--
+		}
+		p.cells[cell] = s
+
+		if p.used == 0 {
+			delete(t.pages, page)
+		}
+	}
+	return nil
+}
+
+func (t *sparseTape) extent() (min, max int, nonBlank bool) {
+	for page, p := range t.pages {
+		for cell, s := range p.cells {
+			if s == nil {
+				continue
+			}
+			pos := page<<sparseTapePageBits + cell
+			if !nonBlank || pos < min {
+				min = pos
+			}
+			if !nonBlank || pos > max {
+				max = pos
+			}
+			nonBlank = true
+		}
+	}
+	return min, max, nonBlank
+}
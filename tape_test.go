@@ -0,0 +1,188 @@
+package turing_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+// tapes are the tapes of the package that are infinite in both directions.
+var tapes = []struct {
+	name string
+	new  func() turing.Tape
+}{
+	{"Infinite", turing.NewInfiniteTape},
+	{"Sparse", turing.NewSparseTape},
+}
+
+// TestTapes runs the same tests on all tapes of the package.
+func TestTapes(t *testing.T) {
+	for _, tt := range tapes {
+		t.Run(tt.name+"Blank", func(t *testing.T) {
+			t.Log("should start with blank symbols on all positions")
+
+			tape := tt.new()
+			for _, pos := range []int{0, 1, -1, 63, 64, -64, -65, 1 << 40, -1 << 40} {
+				symbol, err := tape.Get(pos)
+				if assert.NoError(t, err, pos) {
+					assert.Nil(t, symbol, pos)
+				}
+			}
+		})
+
+		t.Run(tt.name+"Scattered", func(t *testing.T) {
+			t.Log("should keep symbols written far from each other")
+
+			tape := tt.new()
+			positions := []int{0, 5000, -5000, 63, 64, -64, -65, 12345, -54321}
+			for i, pos := range positions {
+				assert.NoError(t, tape.Set(pos, i))
+			}
+			for i, pos := range positions {
+				symbol, err := tape.Get(pos)
+				if assert.NoError(t, err, pos) {
+					assert.Equal(t, i, symbol, pos)
+				}
+			}
+		})
+
+		t.Run(tt.name+"SetArray", func(t *testing.T) {
+			t.Log("should set an array of symbols across the origin, overwriting and erasing")
+
+			tape := tt.new()
+			symbols := make([]turing.Symbol, 200)
+			for i := range symbols {
+				symbols[i] = i
+			}
+			assert.NoError(t, tape.Set(-100, symbols...))
+			assert.NoError(t, tape.Set(-10, "a", nil, "b"))
+			assert.NoError(t, tape.Set(95, "c", "d", "e", "f", "g", "h"))
+
+			for pos := -101; pos < 102; pos++ {
+				var expected turing.Symbol
+				switch {
+				case pos == -10:
+					expected = "a"
+				case pos == -9:
+					expected = nil
+				case pos == -8:
+					expected = "b"
+				case pos >= 95 && pos <= 100:
+					expected = string(rune('c' + pos - 95))
+				case pos >= -100 && pos < 100:
+					expected = pos + 100
+				}
+				symbol, err := tape.Get(pos)
+				if assert.NoError(t, err, pos) {
+					assert.Equal(t, expected, symbol, pos)
+				}
+			}
+		})
+
+		t.Run(tt.name+"Erase", func(t *testing.T) {
+			t.Log("should erase symbols writing the blank symbol")
+
+			tape := tt.new()
+			assert.NoError(t, tape.Set(1000, 1, 2, 3))
+			assert.NoError(t, tape.Set(1000, nil, nil, nil))
+			assert.NoError(t, tape.Set(1001, 4))
+			for pos, expected := range map[int]turing.Symbol{999: nil, 1000: nil, 1001: 4, 1002: nil} {
+				symbol, err := tape.Get(pos)
+				if assert.NoError(t, err, pos) {
+					assert.Equal(t, expected, symbol, pos)
+				}
+			}
+		})
+
+		t.Run(tt.name+"Loop", func(t *testing.T) {
+			t.Log("should find translated cycles after the last symbol")
+
+			state := turing.State{"state", false}
+			program := turing.Program{}
+			program.AddOp(turing.Op{state, turing.ANY, 1, turing.LEFT, state})
+			tape := tt.new()
+			tape.Set(-300, 2)
+			head := turing.Head{}
+			head.Attach(tape, 0)
+			machine := turing.Machine{Head: &head, Program: &program, State: state, LoopDetector: turing.NewLoopDetector()}
+			result, err := machine.RunContext(context.Background(), 10000)
+			if assert.NoError(t, err) {
+				assert.Equal(t, turing.Looping, result.Reason)
+				assert.Equal(t, -1, result.Cycle.Translation)
+				assert.True(t, result.Cycle.Start > 300, "cycle starts after the last symbol, got %v", result.Cycle)
+			}
+		})
+
+		t.Run(tt.name+"Machine", func(t *testing.T) {
+			t.Log("should run a machine and snapshot its configuration")
+
+			start, program := createSeparate01()
+			tape := tt.new()
+			tape.Set(-2, 0, 1, 0, 1, 0, 1, 1, 1, 0, 1)
+			head := turing.Head{}
+			head.Attach(tape, -2)
+			machine := turing.Machine{Head: &head, Program: program, State: start}
+			if !assert.NoError(t, machine.Run()) {
+				return
+			}
+			assert.Equal(t, 33, machine.Steps())
+			assert.Equal(t, " -3: <nil>\n -2: 0\n -1: 0\n 0: 0\n 1: 0\n[2: 1]\n 3: 1\n 4: 1\n 5: 1\n 6: 1\n 7: 1\n 8: <nil>\n",
+				head.PrintTape(-3, 8))
+
+			snapshot, err := machine.Snapshot()
+			if !assert.NoError(t, err) {
+				return
+			}
+			restored := turing.Machine{}
+			if assert.NoError(t, restored.Restore(snapshot)) {
+				assert.Equal(t, head.PrintTape(-3, 8), restored.Head.PrintTape(-3, 8))
+			}
+		})
+	}
+}
+
+// BenchmarkTapes writes sweeps of increasing length on the tapes. A constant
+// ns/cell shows the sweep has linear total cost.
+func BenchmarkTapes(b *testing.B) {
+	sweeps := []struct {
+		name      string
+		direction int
+	}{
+		{"Left", -1},
+		{"Right", 1},
+	}
+
+	for _, tt := range tapes {
+		for _, sweep := range sweeps {
+			for _, cells := range []int{1000, 10000, 100000} {
+				b.Run(fmt.Sprintf("%s/%s/%d", tt.name, sweep.name, cells), func(b *testing.B) {
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						tape := tt.new()
+						for pos := 0; pos < cells; pos++ {
+							tape.Set(pos*sweep.direction, 1)
+						}
+					}
+				})
+			}
+		}
+
+		for _, steps := range []int{1000, 10000, 100000} {
+			b.Run(fmt.Sprintf("%s/Machine/%d", tt.name, steps), func(b *testing.B) {
+				state := turing.State{"left", false}
+				program := turing.Program{}
+				program.AddOp(turing.Op{State: state, Symbol: turing.ANY, WriteSymbol: 1, Movement: turing.LEFT, NextState: state})
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					head := turing.Head{}
+					head.Attach(tt.new(), 0)
+					machine := turing.Machine{Head: &head, Program: &program, State: state}
+					machine.RunContext(context.Background(), steps)
+				}
+			})
+		}
+	}
+}
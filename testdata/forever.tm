@@ -0,0 +1,4 @@
+# Writes 1 moving right, forever.
+start write
+
+write * -> 1 R write
@@ -0,0 +1,15 @@
+start get1
+halt halt
+
+back0 _ -> 0 R get1
+back0 * -> * L back0
+back1 _ -> 1 S halt
+back1 * -> * L back1
+get0 _ -> _ L back1
+get0 0 -> 1 L back0
+get0 1 -> 1 R get0
+get1 _ -> _ S halt
+get1 0 -> 0 R get1
+get1 1 -> _ R get0
+
+tape 0 1 0 1 0 1 1 1 0 1
@@ -0,0 +1,363 @@
+package turing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	textArrow = "->"
+	textBlank = "_"
+	textAny   = "*"
+)
+
+// textToken is a word or a quoted string of a text definition line.
+type textToken struct {
+	text   string
+	quoted bool
+	col    int
+}
+
+// textMaxLine is the maximum length of a text definition line, in bytes.
+const textMaxLine = 1 << 20
+
+// textError is an error on a text definition position.
+type textError struct {
+	line int
+	col  int
+	msg  string
+}
+
+func (e *textError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.line, e.col, e.msg)
+}
+
+// ParseText reads a machine definition in the transition table text format.
+//
+// Each line has one rule or declaration, and '#' starts a comment until
+// the end of the line:
+//
+//	# zero all symbols until a blank
+//	start "zero all"
+//	halt halt
+//	"zero all" _ -> _ S halt
+//	"zero all" * -> 0 R "zero all"
+//	tape 1 0 1
+//
+// A rule is "state symbol -> write move next". The move is L, R or S, or
+// the movement names left, right and stay.
+//
+// Symbols are '_' for the blank symbol, integers or strings. The '*' symbol
+// is ANY when it is the read symbol and KEEP when it is the write symbol.
+// Symbols and state names with spaces or special characters are written as
+// Go quoted strings, and a quoted symbol is always a string.
+//
+// The start declaration sets the start state, which is the state of the
+// first rule when it is not declared. The halt declaration lists the
+// halting states. The tape declaration lists the symbols written on the
+// tape from position 0, where the head starts.
+func ParseText(r io.Reader) (*Definition, error) {
+	var rules []Op
+	var start *textToken
+	var startLine int
+	var tape []Symbol
+	halts := make(map[string]bool)
+	seen := make(map[State]map[Symbol]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, textMaxLine)
+	line := 1
+	for ; scanner.Scan(); line++ {
+		tokens, err := tokenizeText(scanner.Text())
+		if err != nil {
+			err.line = line
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		fail := func(token textToken, format string, args ...interface{}) error {
+			return &textError{line: line, col: token.col, msg: fmt.Sprintf(format, args...)}
+		}
+
+		if len(tokens) > 2 && isBare(tokens[2], textArrow) {
+			if len(tokens) != 6 {
+				return nil, fail(tokens[0], "rule must be \"state symbol -> write move next\"")
+			}
+			op := Op{
+				State:     State{Name: tokens[0].text},
+				Symbol:    parseTextSymbol(tokens[1], ANY),
+				NextState: State{Name: tokens[5].text},
+			}
+			if op.Symbol == KEEP {
+				return nil, fail(tokens[1], "invalid read symbol")
+			}
+			op.WriteSymbol = parseTextSymbol(tokens[3], KEEP)
+			if op.Movement = parseTextMovement(tokens[4]); op.Movement == "" {
+				return nil, fail(tokens[4], "unknown movement %q", tokens[4].text)
+			}
+
+			if seen[op.State] == nil {
+				seen[op.State] = make(map[Symbol]int)
+			}
+			if first, exists := seen[op.State][op.Symbol]; exists {
+				return nil, fail(tokens[0], "duplicated rule for state %s and symbol %s, first defined on line %d",
+					op.State.Name, tokens[1].text, first)
+			}
+			seen[op.State][op.Symbol] = line
+			rules = append(rules, op)
+			continue
+		}
+
+		keyword := tokens[0]
+		switch {
+		case isBare(keyword, "start"):
+			if len(tokens) != 2 {
+				return nil, fail(keyword, "start must declare one state")
+			}
+			if start != nil {
+				return nil, fail(keyword, "start already declared on line %d", startLine)
+			}
+			start = &tokens[1]
+			startLine = line
+		case isBare(keyword, "halt"):
+			if len(tokens) < 2 {
+				return nil, fail(keyword, "halt must declare at least one state")
+			}
+			for _, token := range tokens[1:] {
+				halts[token.text] = true
+			}
+		case isBare(keyword, "tape"):
+			for _, token := range tokens[1:] {
+				tape = append(tape, parseTextSymbol(token, nil))
+			}
+		default:
+			return nil, fail(keyword, "expected a rule or a start, halt or tape declaration")
+		}
+	}
+	if err := scanner.Err(); err == bufio.ErrTooLong {
+		return nil, &textError{line: line, col: 1, msg: fmt.Sprintf("line is longer than %d bytes", textMaxLine)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	state := func(name string) State {
+		return State{Name: name, Halt: halts[name]}
+	}
+
+	def := Definition{Program: &Program{}, Tape: tape}
+	switch {
+	case start != nil:
+		def.Start = state(start.text)
+	case len(rules) > 0:
+		def.Start = state(rules[0].State.Name)
+	default:
+		return nil, fmt.Errorf("no start state")
+	}
+
+	for _, op := range rules {
+		op.State = state(op.State.Name)
+		op.NextState = state(op.NextState.Name)
+		def.Program.AddOp(op)
+	}
+
+	return &def, nil
+}
+
+// FormatText writes the definition in the text format read by ParseText.
+//
+// It writes the start and halt declarations, the rules ordered by state
+// name and symbol, and the tape declaration. Symbols must be nil, integers
+// or strings, and the tape must start on position 0 with the head on it.
+func FormatText(w io.Writer, d *Definition) error {
+	if d.TapeOffset != 0 || d.HeadPos != 0 {
+		return fmt.Errorf("text format tapes start on position 0 with the head on it")
+	}
+
+	states := d.states()
+	names := make(map[string]bool, len(states))
+	var halts []string
+	for _, s := range states {
+		if names[s.Name] {
+			return fmt.Errorf("state %q is both halting and not halting", s.Name)
+		}
+		names[s.Name] = true
+		if s.Halt {
+			halts = append(halts, formatTextName(s.Name))
+		}
+	}
+
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "start %s\n", formatTextName(d.Start.Name))
+	if len(halts) > 0 {
+		fmt.Fprintf(&builder, "halt %s\n", strings.Join(halts, " "))
+	}
+
+	var ops []Op
+	if d.Program != nil {
+		ops = d.Program.SortedOps()
+	}
+	if len(ops) > 0 {
+		builder.WriteString("\n")
+	}
+	for _, op := range ops {
+		move, ok := textMovements[op.Movement]
+		if !ok {
+			return fmt.Errorf("op %v %v: unknown movement %q", op.State, op.Symbol, op.Movement)
+		}
+		read, err := formatTextSymbol(op.Symbol, ANY)
+		if err != nil {
+			return fmt.Errorf("op %v %v: read: %s", op.State, op.Symbol, err.Error())
+		}
+		write, err := formatTextSymbol(op.WriteSymbol, KEEP)
+		if err != nil {
+			return fmt.Errorf("op %v %v: write: %s", op.State, op.Symbol, err.Error())
+		}
+		fmt.Fprintf(&builder, "%s %s %s %s %s %s\n",
+			formatTextName(op.State.Name), read, textArrow, write, move, formatTextName(op.NextState.Name))
+	}
+
+	if len(d.Tape) > 0 {
+		symbols := make([]string, len(d.Tape))
+		for i, s := range d.Tape {
+			symbol, err := formatTextSymbol(s, nil)
+			if err != nil {
+				return fmt.Errorf("tape %d: %s", i, err.Error())
+			}
+			symbols[i] = symbol
+		}
+		fmt.Fprintf(&builder, "\ntape %s\n", strings.Join(symbols, " "))
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+var textMovements = map[string]string{
+	LEFT:  "L",
+	RIGHT: "R",
+	STAY:  "S",
+}
+
+// tokenizeText splits a line in words and quoted strings, until a comment.
+func tokenizeText(line string) ([]textToken, *textError) {
+	var tokens []textToken
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '#':
+			return tokens, nil
+		case r == '"':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != '"'; end++ {
+				if runes[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(runes) {
+				return nil, &textError{col: i + 1, msg: "unterminated quoted string"}
+			}
+			text, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, &textError{col: i + 1, msg: "invalid quoted string"}
+			}
+			tokens = append(tokens, textToken{text: text, quoted: true, col: i + 1})
+			i = end + 1
+		default:
+			end := i
+			for ; end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' && runes[end] != '#'; end++ {
+			}
+			tokens = append(tokens, textToken{text: string(runes[i:end]), col: i + 1})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// isBare tells if the token is the unquoted text.
+func isBare(token textToken, text string) bool {
+	return !token.quoted && token.text == text
+}
+
+// parseTextSymbol converts a token to a symbol, '*' is converted to the
+// wildcard symbol.
+func parseTextSymbol(token textToken, wildcard Symbol) Symbol {
+	if token.quoted {
+		return token.text
+	}
+	switch token.text {
+	case textBlank:
+		return nil
+	case textAny:
+		if wildcard != nil {
+			return wildcard
+		}
+	}
+	if n, err := strconv.Atoi(token.text); err == nil {
+		return n
+	}
+	return token.text
+}
+
+func parseTextMovement(token textToken) string {
+	if token.quoted {
+		return ""
+	}
+	switch strings.ToLower(token.text) {
+	case "l", LEFT:
+		return LEFT
+	case "r", RIGHT:
+		return RIGHT
+	case "s", STAY:
+		return STAY
+	}
+	return ""
+}
+
+// isTextWord tells if the text can be written without quotes.
+func isTextWord(text string) bool {
+	if text == "" || text == textArrow || text == textBlank || text == textAny {
+		return false
+	}
+	for _, r := range text {
+		if unicode.IsSpace(r) || r == '"' || r == '#' || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatTextName(name string) string {
+	if isTextWord(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// formatTextSymbol converts a symbol to its text, the wildcard symbol is
+// converted to '*'.
+func formatTextSymbol(s Symbol, wildcard Symbol) (string, error) {
+	if wildcard != nil && s == wildcard {
+		return textAny, nil
+	}
+	switch v := s.(type) {
+	case nil:
+		return textBlank, nil
+	case int:
+		return strconv.Itoa(v), nil
+	case string:
+		if _, err := strconv.Atoi(v); err != nil && isTextWord(v) {
+			return v, nil
+		}
+		return strconv.Quote(v), nil
+	default:
+		return "", fmt.Errorf("unsupported symbol type %T", s)
+	}
+}
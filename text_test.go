@@ -0,0 +1,175 @@
+package turing_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText(t *testing.T) {
+	t.Run("Parse", func(t *testing.T) {
+		t.Log("should parse a machine definition")
+
+		file, err := os.Open("testdata/separate01.tm")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer file.Close()
+
+		def, err := turing.ParseText(file)
+		if assert.NoError(t, err) {
+			start, program := createSeparate01()
+			assert.Equal(t, start, def.Start)
+			assert.ElementsMatch(t, program.ListOps(), def.Program.ListOps())
+			assert.Equal(t, []turing.Symbol{0, 1, 0, 1, 0, 1, 1, 1, 0, 1}, def.Tape)
+		}
+	})
+
+	t.Run("ParseSyntax", func(t *testing.T) {
+		t.Log("should parse comments, quoted strings and movement names")
+
+		text := `
+			# a comment line
+			"zero all" "1" -> "_" right "zero all" # a rule comment
+			"zero all" 1 -> "*" LEFT "zero all"
+			"zero all" * -> * stay "the end"
+			"zero all" _ -> "a \"b\"" s halt#no space comment
+			halt "the end" halt
+			tape "x y" _ * -2
+		`
+
+		def, err := turing.ParseText(strings.NewReader(text))
+		if assert.NoError(t, err) {
+			zeroAll := turing.State{"zero all", false}
+			end := turing.State{"the end", true}
+			halt := turing.State{"halt", true}
+
+			assert.Equal(t, zeroAll, def.Start)
+			assert.ElementsMatch(t, []turing.Op{
+				{zeroAll, "1", "_", turing.RIGHT, zeroAll},
+				{zeroAll, 1, "*", turing.LEFT, zeroAll},
+				{zeroAll, turing.ANY, turing.KEEP, turing.STAY, end},
+				{zeroAll, nil, `a "b"`, turing.STAY, halt},
+			}, def.Program.ListOps())
+			assert.Equal(t, []turing.Symbol{"x y", nil, "*", -2}, def.Tape)
+		}
+	})
+
+	t.Run("ParseErrors", func(t *testing.T) {
+		t.Log("should report the line and column of errors")
+
+		tests := []struct {
+			text string
+			err  string
+		}{
+			{"a 1 -> 1 R", `line 1, column 1: rule must be "state symbol -> write move next"`},
+			{"a 1 -> 1 R b\n  a 1 -> 0 L b", "line 2, column 3: duplicated rule for state a and symbol 1, first defined on line 1"},
+			{"a 1 -> 1 up b", `line 1, column 10: unknown movement "up"`},
+			{"a 1 -> 1 \"R\" b", `line 1, column 10: unknown movement "R"`},
+			{"start a\nstart b", "line 2, column 1: start already declared on line 1"},
+			{"start", "line 1, column 1: start must declare one state"},
+			{"\n\nhalt", "line 3, column 1: halt must declare at least one state"},
+			{"a 1 => 1 R b", "line 1, column 1: expected a rule or a start, halt or tape declaration"},
+			{`a "1 -> 1 R b`, "line 1, column 3: unterminated quoted string"},
+			{`a "\q" -> 1 R b`, "line 1, column 3: invalid quoted string"},
+			{"# nothing", "no start state"},
+			{"start a\n# " + strings.Repeat("x", 1<<20), "line 2, column 1: line is longer than 1048576 bytes"},
+		}
+		for _, tt := range tests {
+			_, err := turing.ParseText(strings.NewReader(tt.text))
+			assert.EqualError(t, err, tt.err, tt.text)
+		}
+	})
+
+	t.Run("ParseLongLines", func(t *testing.T) {
+		t.Log("should parse lines longer than the default scanner buffer")
+
+		def, err := turing.ParseText(strings.NewReader("start a\nhalt a\ntape" + strings.Repeat(" 1", 100000)))
+		if assert.NoError(t, err) {
+			assert.Len(t, def.Tape, 100000)
+		}
+	})
+
+	t.Run("Format", func(t *testing.T) {
+		t.Log("should format a definition in canonical form")
+
+		expected, err := ioutil.ReadFile("testdata/separate01.tm")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		start, program := createSeparate01()
+		def := turing.Definition{
+			Program: program,
+			Start:   start,
+			Tape:    []turing.Symbol{0, 1, 0, 1, 0, 1, 1, 1, 0, 1},
+		}
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.FormatText(&builder, &def)) {
+			assert.Equal(t, string(expected), builder.String())
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Log("should parse a formatted definition to the same definition")
+
+		state := turing.State{"a state", false}
+		halt := turing.State{"->", true}
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, "*", "_", turing.RIGHT, state})
+		program.AddOp(turing.Op{state, turing.ANY, turing.KEEP, turing.LEFT, state})
+		program.AddOp(turing.Op{state, nil, "#", turing.STAY, halt})
+		program.AddOp(turing.Op{state, "12", -12, turing.STAY, halt})
+		program.AddOp(turing.Op{state, "", "é", turing.STAY, halt})
+
+		def := turing.Definition{Program: &program, Start: state, Tape: []turing.Symbol{"*", nil, 3, "3"}}
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.FormatText(&builder, &def)) {
+			assert.Equal(t, ""+
+				"start \"a state\"\n"+
+				"halt \"->\"\n"+
+				"\n"+
+				"\"a state\" _ -> \"#\" S \"->\"\n"+
+				"\"a state\" \"\" -> é S \"->\"\n"+
+				"\"a state\" \"*\" -> \"_\" R \"a state\"\n"+
+				"\"a state\" \"12\" -> -12 S \"->\"\n"+
+				"\"a state\" * -> * L \"a state\"\n"+
+				"\n"+
+				"tape \"*\" _ 3 \"3\"\n",
+				builder.String())
+
+			decoded, err := turing.ParseText(strings.NewReader(builder.String()))
+			if assert.NoError(t, err) {
+				assert.Equal(t, def.Start, decoded.Start)
+				assert.Equal(t, def.Tape, decoded.Tape)
+				assert.Equal(t, program.SortedOps(), decoded.Program.SortedOps())
+			}
+		}
+	})
+
+	t.Run("FormatErrors", func(t *testing.T) {
+		t.Log("should not format definitions that can't be parsed")
+
+		state := turing.State{"state", false}
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, 1.5, 1, turing.RIGHT, state})
+		err := turing.FormatText(&strings.Builder{}, &turing.Definition{Program: &program, Start: state})
+		assert.EqualError(t, err, "op state 1.5: read: unsupported symbol type float64")
+
+		program = turing.Program{}
+		program.AddOp(turing.Op{state, 1, 1, "up", state})
+		err = turing.FormatText(&strings.Builder{}, &turing.Definition{Program: &program, Start: state})
+		assert.EqualError(t, err, `op state 1: unknown movement "up"`)
+
+		err = turing.FormatText(&strings.Builder{}, &turing.Definition{Program: &turing.Program{}, Start: state, HeadPos: 1})
+		assert.EqualError(t, err, "text format tapes start on position 0 with the head on it")
+	})
+}
@@ -0,0 +1,303 @@
+package turing
+
+import "fmt"
+
+// Timeline is an Observer that records a run so the machine can go back to
+// any recorded step.
+//
+// A timeline created by NewTimeline keeps undo information for every step.
+// One created by NewCheckpointTimeline keeps a checkpoint of the machine
+// every few steps instead, to bound the memory used by long runs, and goes
+// back to a step by restoring the checkpoint before it and executing the
+// steps after it again. When it reaches the maximum number of checkpoints,
+// it drops every other checkpoint and doubles the interval between them.
+//
+// The timeline must be the machine observer, or one of its observers, from
+// its creation on. When it takes the machine back, it resets the machine
+// LoopDetector and rewinds the machine observer, when it is a Rewinder, and
+// the steps executed again are sent to the observer like any other step.
+type Timeline struct {
+	machine *Machine
+	first   int
+	last    int
+	minPos  int
+	maxPos  int
+
+	// undo has one entry for each step after first, when recording every
+	// step.
+	undo []undoStep
+
+	// interval is the number of steps between checkpoints, 0 when recording
+	// every step.
+	interval       int
+	maxCheckpoints int
+	checkpoints    []timelineCheckpoint
+	// initial has the symbols of the cells when the head first reached
+	// them, when keeping checkpoints.
+	initial map[int]Symbol
+	// pending has the cells changed since the last checkpoint.
+	pending map[int]Symbol
+}
+
+// undoStep is what a step changed on the machine.
+type undoStep struct {
+	pos    int
+	read   Symbol
+	state  State
+	minPos int
+	maxPos int
+}
+
+// timelineCheckpoint is the machine configuration at a step. The tape is
+// not copied, changed has only the cells changed since the previous
+// checkpoint.
+type timelineCheckpoint struct {
+	step    int
+	state   State
+	pos     int
+	minPos  int
+	maxPos  int
+	changed map[int]Symbol
+}
+
+// NewTimeline creates a timeline that records every step of the machine,
+// starting on its current step.
+func NewTimeline(m *Machine) *Timeline {
+	return &Timeline{
+		machine: m,
+		first:   m.steps,
+		last:    m.steps,
+		minPos:  m.Head.minPos,
+		maxPos:  m.Head.maxPos,
+	}
+}
+
+// NewCheckpointTimeline creates a timeline that records a checkpoint of the
+// machine, starting on its current step, and every interval steps after it,
+// keeping at most maxCheckpoints checkpoints.
+func NewCheckpointTimeline(m *Machine, interval, maxCheckpoints int) (*Timeline, error) {
+	if interval < 1 {
+		return nil, fmt.Errorf("checkpoint interval must be positive, got %d", interval)
+	}
+	if maxCheckpoints < 2 {
+		return nil, fmt.Errorf("maximum checkpoints must be at least 2, got %d", maxCheckpoints)
+	}
+	t := NewTimeline(m)
+	t.interval = interval
+	t.maxCheckpoints = maxCheckpoints
+	t.initial = make(map[int]Symbol)
+	t.pending = make(map[int]Symbol)
+	for pos := t.minPos; pos <= t.maxPos; pos++ {
+		symbol, err := m.Head.tape.Get(pos)
+		if err != nil {
+			return nil, err
+		}
+		t.initial[pos] = symbol
+	}
+	t.checkpoint()
+	return t, nil
+}
+
+// First returns the first recorded step.
+func (t *Timeline) First() int {
+	return t.first
+}
+
+// Last returns the last recorded step.
+func (t *Timeline) Last() int {
+	return t.last
+}
+
+// Checkpoints returns the number of checkpoints kept, 0 when recording every
+// step.
+func (t *Timeline) Checkpoints() int {
+	return len(t.checkpoints)
+}
+
+// Interval returns the number of steps between checkpoints, 0 when recording
+// every step.
+func (t *Timeline) Interval() int {
+	return t.interval
+}
+
+// Observe records a step.
+func (t *Timeline) Observe(e StepEvent) {
+	minPos, maxPos := t.minPos, t.maxPos
+	t.minPos, t.maxPos = t.machine.Head.minPos, t.machine.Head.maxPos
+	t.last = e.Step
+
+	if t.interval == 0 {
+		t.undo = append(t.undo, undoStep{pos: e.From, read: e.Read, state: e.State, minPos: minPos, maxPos: maxPos})
+		return
+	}
+
+	for _, pos := range []int{t.minPos, t.maxPos} {
+		if pos < minPos || pos > maxPos {
+			t.initial[pos], _ = t.machine.Head.tape.Get(pos)
+		}
+	}
+	if e.Written != e.Read {
+		t.pending[e.From] = e.Written
+	}
+
+	if (e.Step-t.first)%t.interval != 0 {
+		return
+	}
+	t.checkpoint()
+	if len(t.checkpoints) > t.maxCheckpoints {
+		t.thin()
+	}
+}
+
+// checkpoint records the current machine configuration with the cells
+// changed since the last checkpoint.
+func (t *Timeline) checkpoint() {
+	m := t.machine
+	t.checkpoints = append(t.checkpoints, timelineCheckpoint{
+		step:    m.steps,
+		state:   m.State,
+		pos:     m.Head.pos,
+		minPos:  m.Head.minPos,
+		maxPos:  m.Head.maxPos,
+		changed: t.pending,
+	})
+	t.pending = make(map[int]Symbol)
+}
+
+// thin drops every other checkpoint, keeping the first, and doubles the
+// interval. The cells changed on a dropped checkpoint move to the next one.
+func (t *Timeline) thin() {
+	kept := t.checkpoints[:1]
+	for i := 1; i < len(t.checkpoints); i += 2 {
+		dropped := t.checkpoints[i]
+		if i+1 == len(t.checkpoints) {
+			t.pending = merge(dropped.changed, t.pending)
+			break
+		}
+		next := t.checkpoints[i+1]
+		next.changed = merge(dropped.changed, next.changed)
+		kept = append(kept, next)
+	}
+	for i := len(kept); i < len(t.checkpoints); i++ {
+		t.checkpoints[i] = timelineCheckpoint{}
+	}
+	t.checkpoints = kept
+	t.interval *= 2
+}
+
+// merge copies the later changes into the earlier ones and returns them.
+func merge(earlier, later map[int]Symbol) map[int]Symbol {
+	for pos, symbol := range later {
+		earlier[pos] = symbol
+	}
+	return earlier
+}
+
+// StepBack takes the machine back to the step before the current one.
+func (t *Timeline) StepBack() error {
+	return t.Goto(t.machine.steps - 1)
+}
+
+// Goto takes the machine to the step, going back to a recorded step or
+// executing the steps after the current one.
+func (t *Timeline) Goto(step int) error {
+	m := t.machine
+	if m.steps != t.last {
+		return fmt.Errorf("machine is on step %d, but the last recorded step is %d", m.steps, t.last)
+	}
+	if step < t.first {
+		return fmt.Errorf("step %d is before the first recorded step %d", step, t.first)
+	}
+
+	if step < m.steps {
+		var err error
+		if t.interval == 0 {
+			err = t.undoTo(step)
+		} else {
+			err = t.restoreBefore(step)
+		}
+		if err != nil {
+			return err
+		}
+		t.rewind()
+	}
+
+	for m.steps < step {
+		if err := m.Step(); err != nil {
+			return fmt.Errorf("step %d: %w", m.steps+1, err)
+		}
+		if m.steps != t.last {
+			return fmt.Errorf("timeline is not observing the machine")
+		}
+	}
+	return nil
+}
+
+// rewind tells the machine loop detector and observer that the machine went
+// back to its current step.
+func (t *Timeline) rewind() {
+	m := t.machine
+	if m.LoopDetector != nil {
+		m.LoopDetector.Reset()
+	}
+	if r, ok := m.Observer.(Rewinder); ok {
+		r.Rewind(m.steps)
+	}
+}
+
+// undoTo undoes the steps after step.
+func (t *Timeline) undoTo(step int) error {
+	m := t.machine
+	for m.steps > step {
+		undo := t.undo[len(t.undo)-1]
+		if err := m.Head.tape.Set(undo.pos, undo.read); err != nil {
+			return err
+		}
+		m.Head.pos = undo.pos
+		m.Head.minPos, m.Head.maxPos = undo.minPos, undo.maxPos
+		m.State = undo.state
+		m.steps--
+
+		t.undo = t.undo[:len(t.undo)-1]
+		t.minPos, t.maxPos = undo.minPos, undo.maxPos
+		t.last = m.steps
+	}
+	return nil
+}
+
+// restoreBefore restores the last checkpoint not after step, and forgets the
+// checkpoints after it.
+func (t *Timeline) restoreBefore(step int) error {
+	i := len(t.checkpoints) - 1
+	for t.checkpoints[i].step > step {
+		i--
+	}
+	checkpoint := t.checkpoints[i]
+
+	m := t.machine
+	cells := make([]Symbol, m.Head.maxPos-m.Head.minPos+1)
+	for j := range cells {
+		cells[j] = t.initial[m.Head.minPos+j]
+	}
+	for _, c := range t.checkpoints[:i+1] {
+		for pos, symbol := range c.changed {
+			cells[pos-m.Head.minPos] = symbol
+		}
+	}
+	if err := m.Head.tape.Set(m.Head.minPos, cells...); err != nil {
+		return err
+	}
+	m.Head.pos = checkpoint.pos
+	m.Head.minPos, m.Head.maxPos = checkpoint.minPos, checkpoint.maxPos
+	m.State = checkpoint.state
+	m.steps = checkpoint.step
+
+	for j := i + 1; j < len(t.checkpoints); j++ {
+		t.checkpoints[j] = timelineCheckpoint{}
+	}
+	t.checkpoints = t.checkpoints[:i+1]
+	t.pending = make(map[int]Symbol)
+	t.minPos, t.maxPos = checkpoint.minPos, checkpoint.maxPos
+	t.last = checkpoint.step
+	return nil
+}
@@ -0,0 +1,247 @@
+package turing_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeline(t *testing.T) {
+	// createMachine creates the separate01 machine after the steps.
+	createMachine := func(steps int) *turing.Machine {
+		start, program := createSeparate01()
+		def := turing.Definition{Program: program, Start: start, Tape: []turing.Symbol{0, 1, 0, 1, 0, 1, 1, 1, 0, 1}, HeadPos: 2}
+		machine := def.NewMachine()
+		for i := 0; i < steps; i++ {
+			machine.Step()
+		}
+		return machine
+	}
+	configuration := func(m *turing.Machine) string {
+		return fmt.Sprintf("step %d, state %v, pos %d, min %d, max %d\n%s",
+			m.Steps(), m.State, m.Head.Pos(), m.Head.MinPos(), m.Head.MaxPos(), m.Head.PrintTape(-2, 12))
+	}
+	const halt = 24
+
+	timelines := []struct {
+		name string
+		new  func(m *turing.Machine) *turing.Timeline
+	}{
+		{"Undo", turing.NewTimeline},
+		{"Checkpoint", func(m *turing.Machine) *turing.Timeline {
+			timeline, err := turing.NewCheckpointTimeline(m, 4, 3)
+			if err != nil {
+				panic(err)
+			}
+			return timeline
+		}},
+	}
+	for _, tl := range timelines {
+		t.Run(tl.name+"StepBack", func(t *testing.T) {
+			t.Log("should step back through all recorded steps")
+
+			machine := createMachine(0)
+			timeline := tl.new(machine)
+			machine.Observer = timeline
+			if !assert.NoError(t, machine.Run()) {
+				return
+			}
+			assert.Equal(t, halt, machine.Steps())
+			assert.Equal(t, 0, timeline.First())
+			assert.Equal(t, halt, timeline.Last())
+
+			for step := halt - 1; step >= 0; step-- {
+				if !assert.NoError(t, timeline.StepBack()) {
+					return
+				}
+				assert.Equal(t, configuration(createMachine(step)), configuration(machine))
+			}
+			assert.EqualError(t, timeline.StepBack(), "step -1 is before the first recorded step 0")
+		})
+
+		t.Run(tl.name+"Goto", func(t *testing.T) {
+			t.Log("should go back and forward to any step")
+
+			machine := createMachine(3)
+			timeline := tl.new(machine)
+			machine.Observer = timeline
+			if !assert.NoError(t, machine.Run()) {
+				return
+			}
+
+			for _, step := range []int{17, 3, 20, halt, 9, 10, 8, 21} {
+				if assert.NoError(t, timeline.Goto(step), step) {
+					assert.Equal(t, configuration(createMachine(step)), configuration(machine), step)
+				}
+			}
+			assert.EqualError(t, timeline.Goto(2), "step 2 is before the first recorded step 3")
+			assert.EqualError(t, timeline.Goto(halt+1), "step 25: machine is halted, state [halt]")
+			assert.Equal(t, configuration(createMachine(halt)), configuration(machine))
+		})
+
+		t.Run(tl.name+"Rewinders", func(t *testing.T) {
+			t.Log("should rewind the other observers, so they don't see steps twice")
+
+			// observe sets a profiler, a space-time recorder and the timeline
+			// as the machine observers.
+			observe := func(m *turing.Machine) (*turing.Profiler, *turing.SpaceTime, *turing.Timeline) {
+				profiler := turing.NewProfiler(m)
+				spaceTime := turing.NewSpaceTime(m)
+				timeline := tl.new(m)
+				m.Observer = turing.Observers{profiler, spaceTime, timeline}
+				return profiler, spaceTime, timeline
+			}
+			// diagram draws the space-time diagram.
+			diagram := func(s *turing.SpaceTime) string {
+				builder := strings.Builder{}
+				s.WriteSVG(&builder, turing.SpaceTimeOptions{})
+				return builder.String()
+			}
+
+			machine := createMachine(3)
+			profiler, spaceTime, timeline := observe(machine)
+			if !assert.NoError(t, machine.Run()) {
+				return
+			}
+
+			for _, step := range []int{17, 3, 20, 9, 10} {
+				if !assert.NoError(t, timeline.Goto(step), step) {
+					return
+				}
+				expected := createMachine(3)
+				expectedProfiler, expectedSpaceTime, _ := observe(expected)
+				for expected.Steps() < step {
+					expected.Step()
+				}
+
+				profile, expectedProfile := profiler.Profile(), expectedProfiler.Profile()
+				profile.Duration, expectedProfile.Duration = 0, 0
+				assert.Equal(t, expectedProfile, profile, step)
+				assert.Equal(t, expectedSpaceTime.Steps(), spaceTime.Steps(), step)
+				assert.Equal(t, diagram(expectedSpaceTime), diagram(spaceTime), step)
+			}
+		})
+	}
+
+	t.Run("LoopDetector", func(t *testing.T) {
+		t.Log("should reset the loop detector when going back")
+
+		walk := turing.State{"walk", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{walk, turing.ANY, turing.KEEP, turing.RIGHT, walk})
+		head := turing.Head{}
+		head.Attach(turing.NewInfiniteTape(), 0)
+		machine := turing.Machine{Head: &head, Program: &program, State: walk, LoopDetector: turing.NewLoopDetector()}
+		timeline := turing.NewTimeline(&machine)
+		machine.Observer = timeline
+
+		result, err := machine.RunContext(context.Background(), 100)
+		if !assert.NoError(t, err) || !assert.Equal(t, turing.Looping, result.Reason) {
+			return
+		}
+		if !assert.NoError(t, timeline.Goto(0)) {
+			return
+		}
+		result, err = machine.RunContext(context.Background(), 100)
+		if assert.NoError(t, err) {
+			assert.Equal(t, turing.Looping, result.Reason)
+			assert.Equal(t, turing.Cycle{Start: 1, Period: 1, Translation: 1}, result.Cycle)
+		}
+	})
+
+	t.Run("NoOperation", func(t *testing.T) {
+		t.Log("should step back from a no operation error")
+
+		state := turing.State{"state", false}
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, 1, 0, turing.LEFT, state})
+
+		tape := turing.NewInfiniteTape()
+		tape.Set(0, 1, 1, 1)
+		head := turing.Head{}
+		head.Attach(tape, 2)
+		machine := turing.Machine{Head: &head, Program: &program, State: state}
+
+		timeline := turing.NewTimeline(&machine)
+		machine.Observer = timeline
+		assert.Error(t, machine.Run())
+		assert.Equal(t, "[-1: <nil>]\n 0: 0\n 1: 0\n 2: 0\n", head.PrintTape(-1, 2))
+
+		if assert.NoError(t, timeline.StepBack()) {
+			assert.Equal(t, "[0: 1]\n 1: 0\n 2: 0\n", head.PrintTape(0, 2))
+			assert.Equal(t, 0, head.MinPos())
+		}
+	})
+
+	t.Run("NotObserving", func(t *testing.T) {
+		t.Log("should not go to a step when the timeline does not observe the machine")
+
+		machine := createMachine(0)
+		timeline := turing.NewTimeline(machine)
+
+		assert.EqualError(t, timeline.Goto(2), "timeline is not observing the machine")
+		assert.EqualError(t, timeline.StepBack(), "machine is on step 1, but the last recorded step is 0")
+	})
+
+	t.Run("CheckpointInterval", func(t *testing.T) {
+		t.Log("should not create checkpoint timelines without a positive interval")
+
+		_, err := turing.NewCheckpointTimeline(createMachine(0), 0, 2)
+		assert.EqualError(t, err, "checkpoint interval must be positive, got 0")
+
+		_, err = turing.NewCheckpointTimeline(createMachine(0), 1, 1)
+		assert.EqualError(t, err, "maximum checkpoints must be at least 2, got 1")
+	})
+
+	t.Run("CheckpointLimit", func(t *testing.T) {
+		t.Log("should keep a bounded number of checkpoints on long runs")
+
+		// createCounter creates a machine that counts in binary forever.
+		createCounter := func() *turing.Machine {
+			right, inc := turing.State{"right", false}, turing.State{"inc", false}
+			program := turing.Program{}
+			program.AddOp(turing.Op{right, 0, turing.KEEP, turing.RIGHT, right})
+			program.AddOp(turing.Op{right, 1, turing.KEEP, turing.RIGHT, right})
+			program.AddOp(turing.Op{right, nil, turing.KEEP, turing.LEFT, inc})
+			program.AddOp(turing.Op{inc, 1, 0, turing.LEFT, inc})
+			program.AddOp(turing.Op{inc, 0, 1, turing.RIGHT, right})
+			program.AddOp(turing.Op{inc, nil, 1, turing.RIGHT, right})
+			def := turing.Definition{Program: &program, Start: right}
+			return def.NewMachine()
+		}
+		const steps = 200000
+
+		machine := createCounter()
+		timeline, err := turing.NewCheckpointTimeline(machine, 1, 8)
+		if !assert.NoError(t, err) {
+			return
+		}
+		machine.Observer = turing.ObserverFunc(func(e turing.StepEvent) {
+			timeline.Observe(e)
+			if timeline.Checkpoints() > 8 {
+				t.Fatalf("step %d: %d checkpoints", e.Step, timeline.Checkpoints())
+			}
+		})
+		result, err := machine.RunContext(context.Background(), steps)
+		if !assert.NoError(t, err) || !assert.Equal(t, steps, result.Steps) {
+			return
+		}
+		assert.LessOrEqual(t, timeline.Checkpoints(), 8)
+		assert.Greater(t, timeline.Interval(), steps/8)
+
+		for _, step := range []int{123456, 99999, 7} {
+			expected := createCounter()
+			expected.RunContext(context.Background(), step)
+			if assert.NoError(t, timeline.Goto(step), step) {
+				assert.Equal(t, configuration(expected), configuration(machine), step)
+				assert.Equal(t, expected.Head.PrintTape(expected.Head.MinPos(), expected.Head.MaxPos()),
+					machine.Head.PrintTape(machine.Head.MinPos(), machine.Head.MaxPos()), step)
+			}
+		}
+		assert.LessOrEqual(t, timeline.Checkpoints(), 8)
+	})
+}
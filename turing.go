@@ -47,39 +47,95 @@ type Tape interface {
 
 // Machine is a turing machine, it has a head, a program to execute and the
 // initial state.
+//
+// When Observer is set, it receives every step the machine executes. When
+// LoopDetector is set, runs stop when the machine is on a cycle.
 type Machine struct {
-	Head    *Head
-	Program *Program
-	State   State
+	Head         *Head
+	Program      *Program
+	State        State
+	Observer     Observer
+	LoopDetector *LoopDetector
+
+	steps int
 }
 
-// Step executes one step of the machine
+// Steps returns the number of steps the machine executed.
+func (m *Machine) Steps() int {
+	return m.steps
+}
+
+// Step executes one step of the machine. The error is a *StepError, with the
+// configuration where the step failed.
 func (m *Machine) Step() error {
 	if m.State.Halt {
-		return fmt.Errorf("machine is halted, state %s", m.State.String())
+		return m.stepError(nil, newKindError(ErrHalted, "machine is halted, state %s", m.State.String()))
 	}
 	v, err := m.Head.Read()
+	if err != nil {
+		return m.stepError(nil, wrapKindError(ErrTapeRead, err))
+	}
 	oper, err := m.Program.FindOp(m.State, v)
 	if err != nil {
-		return err
+		return m.stepError(v, err)
 	}
 
+	from := m.Head.Pos()
+	to, err := m.Head.destination(oper.Movement)
+	if err != nil {
+		return m.stepError(v, err)
+	}
+	written := v
 	if oper.WriteSymbol != KEEP {
-		m.Head.Write(oper.WriteSymbol)
+		if err := m.Head.Write(oper.WriteSymbol); err != nil {
+			return m.stepError(v, wrapKindError(ErrTapeWrite, err))
+		}
+		written = oper.WriteSymbol
 	}
-	m.Head.Move(oper.Movement)
+	m.Head.moveTo(to)
+	state := m.State
 	m.State = oper.NextState
+	m.steps++
+
+	if m.Observer != nil || m.LoopDetector != nil {
+		event := StepEvent{
+			Step:      m.steps,
+			State:     state,
+			Read:      v,
+			Op:        oper,
+			Written:   written,
+			From:      from,
+			To:        m.Head.Pos(),
+			NextState: m.State,
+		}
+		if m.LoopDetector != nil {
+			m.LoopDetector.observe(m, event)
+		}
+		if m.Observer != nil {
+			m.Observer.Observe(event)
+		}
+	}
 	return nil
 }
 
+// stepError creates the error of the next step, with the symbol under the
+// head.
+func (m *Machine) stepError(symbol Symbol, err error) error {
+	return &StepError{Step: m.steps + 1, State: m.State, Symbol: symbol, Pos: m.Head.Pos(), Err: err}
+}
+
 // Run executes the current program until it reaches a halt state or there is no
-// operation for current state and symbol under head.
+// operation for current state and symbol under head. With a LoopDetector, it
+// also stops with an error when the machine is on a cycle.
 func (m *Machine) Run() error {
 
 	for instr := 1; !m.State.Halt; instr++ {
 		err := m.Step()
 		if err != nil {
-			return fmt.Errorf("Error at instruction %d: %s", instr, err.Error())
+			return fmt.Errorf("Error at instruction %d: %w", instr, err)
+		}
+		if err := m.loopError(); err != nil {
+			return fmt.Errorf("Error at instruction %d: %w", instr, err)
 		}
 	}
 	return nil
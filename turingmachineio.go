@@ -0,0 +1,371 @@
+package turing
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode/utf8"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+const tmioBlank = " "
+
+// tmioDefinition keeps the symbols, states and table as YAML nodes, so the
+// scalars are read as written: y, n, yes, no, on and off are not booleans.
+type tmioDefinition struct {
+	Input yaml.Node `yaml:"input,omitempty"`
+	Blank yaml.Node `yaml:"blank"`
+	Start string    `yaml:"start state"`
+	Table yaml.Node `yaml:"table"`
+}
+
+// ParseTuringMachineIO reads a machine written in the turingmachine.io YAML
+// format.
+//
+// The table has one entry per state, mapping symbols, or lists of symbols,
+// to an action. The action is a direction, L or R, or a map with an
+// optional write symbol and one direction whose value is the next state,
+// the current state when it is empty. States without transitions are
+// halting states.
+//
+// Symbols are single characters, converted to strings, and the blank
+// character is the blank symbol. The input is written on the tape from
+// position 0, one character per position.
+//
+// On turingmachine.io the machine halts when there is no transition for the
+// symbol under the head, while this package reports that there is no
+// operation.
+func ParseTuringMachineIO(r io.Reader) (*Definition, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var td tmioDefinition
+	if err := yaml.Unmarshal(data, &td); err != nil {
+		return nil, fmt.Errorf("invalid turingmachine.io definition: %s", err.Error())
+	}
+
+	if isTMIONull(&td.Blank) {
+		return nil, fmt.Errorf("missing blank symbol")
+	}
+	blank := td.Blank.Value
+	if utf8.RuneCountInString(blank) != 1 {
+		return nil, fmt.Errorf("blank symbol %q must be a single character", blank)
+	}
+	symbol := func(s string) (Symbol, error) {
+		if utf8.RuneCountInString(s) != 1 {
+			return nil, fmt.Errorf("symbol %q must be a single character", s)
+		}
+		if s == blank {
+			return nil, nil
+		}
+		return s, nil
+	}
+
+	table := tmioPairs(&td.Table)
+	states := make(map[string]State, len(table))
+	for _, item := range table {
+		name := item[0].Value
+		if !isTMIONull(item[1]) && item[1].Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("state %s: transitions must be a map", name)
+		}
+		states[name] = State{Name: name, Halt: len(tmioPairs(item[1])) == 0}
+	}
+	state := func(name string) (State, error) {
+		s, ok := states[name]
+		if !ok {
+			return State{}, fmt.Errorf("unknown state %q", name)
+		}
+		return s, nil
+	}
+
+	def := Definition{Program: &Program{}}
+	if def.Start, err = state(td.Start); err != nil {
+		return nil, fmt.Errorf("start state: %s", err.Error())
+	}
+
+	for _, item := range table {
+		current := states[item[0].Value]
+		for _, transition := range tmioPairs(item[1]) {
+			op, err := parseTMIOAction(current, transition[1], state, symbol)
+			if err != nil {
+				return nil, fmt.Errorf("state %s: %s", current.Name, err.Error())
+			}
+
+			keys := []*yaml.Node{transition[0]}
+			if transition[0].Kind == yaml.SequenceNode {
+				keys = transition[0].Content
+			}
+			for _, key := range keys {
+				if op.Symbol, err = symbol(key.Value); err != nil {
+					return nil, fmt.Errorf("state %s: %s", current.Name, err.Error())
+				}
+				def.Program.AddOp(op)
+			}
+		}
+	}
+
+	if !isTMIONull(&td.Input) {
+		for _, r := range td.Input.Value {
+			s, _ := symbol(string(r))
+			def.Tape = append(def.Tape, s)
+		}
+	}
+
+	return &def, nil
+}
+
+// parseTMIOAction converts a transition action to an operation without the
+// read symbol.
+func parseTMIOAction(current State, action *yaml.Node, state func(string) (State, error), symbol func(string) (Symbol, error)) (Op, error) {
+	op := Op{State: current, WriteSymbol: KEEP, NextState: current}
+
+	var items [][2]*yaml.Node
+	switch action.Kind {
+	case yaml.ScalarNode:
+		items = [][2]*yaml.Node{{action, nil}}
+	case yaml.MappingNode:
+		items = tmioPairs(action)
+	default:
+		var v interface{}
+		action.Decode(&v)
+		return op, fmt.Errorf("invalid action %v", v)
+	}
+
+	var err error
+	for _, item := range items {
+		key := item[0].Value
+		switch key {
+		case "write":
+			if op.WriteSymbol, err = symbol(item[1].Value); err != nil {
+				return op, err
+			}
+		case "L", "R":
+			if op.Movement != "" {
+				return op, fmt.Errorf("action has more than one direction")
+			}
+			op.Movement = LEFT
+			if key == "R" {
+				op.Movement = RIGHT
+			}
+			if !isTMIONull(item[1]) {
+				if op.NextState, err = state(item[1].Value); err != nil {
+					return op, err
+				}
+			}
+		default:
+			return op, fmt.Errorf("unsupported action %q", key)
+		}
+	}
+	if op.Movement == "" {
+		return op, fmt.Errorf("action has no direction")
+	}
+
+	return op, nil
+}
+
+// tmioPairs returns the key and value nodes of a map node, in order.
+func tmioPairs(n *yaml.Node) [][2]*yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	pairs := make([][2]*yaml.Node, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{n.Content[i], n.Content[i+1]})
+	}
+	return pairs
+}
+
+// isTMIONull tells if the node is missing or null.
+func isTMIONull(n *yaml.Node) bool {
+	return n == nil || n.Kind == 0 || n.ShortTag() == "!!null"
+}
+
+// tmioString returns a string node, quoted when it would be read as another
+// type.
+func tmioString(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+// tmioNull returns a null node.
+func tmioNull() *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
+
+// FormatTuringMachineIO writes the definition in the turingmachine.io YAML
+// format, with states and transitions ordered by name and symbol.
+//
+// The format has no wildcards, so ANY operations are written for each
+// symbol of the program and tape that has no operation of its own. Symbols
+// must be single character strings or single digit integers, which are read
+// back as strings, and the space is the blank symbol. Halting states can't
+// have operations, the other states must have them, and operations can't
+// STAY.
+func FormatTuringMachineIO(w io.Writer, d *Definition) error {
+	if d.TapeOffset != 0 || d.HeadPos != 0 {
+		return fmt.Errorf("turingmachine.io tapes start on position 0 with the head on it")
+	}
+
+	var ops []Op
+	if d.Program != nil {
+		ops = d.Program.SortedOps()
+	}
+
+	alphabet := []Symbol{nil}
+	known := map[Symbol]bool{nil: true, ANY: true, KEEP: true}
+	for _, op := range ops {
+		for _, s := range []Symbol{op.Symbol, op.WriteSymbol} {
+			if !known[s] {
+				known[s] = true
+				alphabet = append(alphabet, s)
+			}
+		}
+	}
+	input := strings.Builder{}
+	for _, s := range d.Tape {
+		if !known[s] {
+			known[s] = true
+			alphabet = append(alphabet, s)
+		}
+		text, err := formatTMIOSymbol(s)
+		if err != nil {
+			return fmt.Errorf("tape: %s", err.Error())
+		}
+		input.WriteString(text)
+	}
+
+	stateOps := make(map[State][]Op)
+	for _, op := range ops {
+		stateOps[op.State] = append(stateOps[op.State], op)
+	}
+
+	td := tmioDefinition{Blank: *tmioString(tmioBlank), Start: d.Start.Name, Table: yaml.Node{Kind: yaml.MappingNode}}
+	if input.Len() > 0 {
+		td.Input = *tmioString(input.String())
+	}
+
+	for _, s := range d.states() {
+		opers := stateOps[s]
+		if s.Halt {
+			if len(opers) > 0 {
+				return fmt.Errorf("halting state %v has operations", s)
+			}
+			td.Table.Content = append(td.Table.Content, tmioString(s.Name), tmioNull())
+			continue
+		}
+		if len(opers) == 0 {
+			return fmt.Errorf("state %v has no operations and is not halting", s)
+		}
+
+		transitions, err := formatTMIOTransitions(opers, alphabet)
+		if err != nil {
+			return fmt.Errorf("state %v: %s", s, err.Error())
+		}
+		td.Table.Content = append(td.Table.Content, tmioString(s.Name), transitions)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(td); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// formatTMIOTransitions converts the operations of a state to transitions,
+// expanding ANY to the alphabet symbols without operations.
+func formatTMIOTransitions(opers []Op, alphabet []Symbol) (*yaml.Node, error) {
+	transitions := &yaml.Node{Kind: yaml.MappingNode}
+	matched := make(map[Symbol]bool)
+	var anyOp *Op
+	for i, op := range opers {
+		if op.Symbol == ANY {
+			anyOp = &opers[i]
+			continue
+		}
+		matched[op.Symbol] = true
+	}
+
+	for _, op := range opers {
+		if op.Symbol == ANY {
+			continue
+		}
+		key, action, err := formatTMIOTransition(op, op.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		transitions.Content = append(transitions.Content, key, action)
+	}
+	if anyOp != nil {
+		for _, s := range alphabet {
+			if matched[s] {
+				continue
+			}
+			key, action, err := formatTMIOTransition(*anyOp, s)
+			if err != nil {
+				return nil, err
+			}
+			transitions.Content = append(transitions.Content, key, action)
+		}
+	}
+	return transitions, nil
+}
+
+// formatTMIOTransition returns the key and action nodes of the transition
+// of the operation on the read symbol.
+func formatTMIOTransition(op Op, read Symbol) (*yaml.Node, *yaml.Node, error) {
+	text, err := formatTMIOSymbol(read)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := tmioString(text)
+
+	var direction string
+	switch op.Movement {
+	case LEFT:
+		direction = "L"
+	case RIGHT:
+		direction = "R"
+	case STAY:
+		return nil, nil, fmt.Errorf("stay movement is not supported")
+	default:
+		return nil, nil, fmt.Errorf("unknown movement %q", op.Movement)
+	}
+
+	if op.WriteSymbol == KEEP && op.NextState == op.State {
+		return key, tmioString(direction), nil
+	}
+
+	action := &yaml.Node{Kind: yaml.MappingNode}
+	if op.WriteSymbol != KEEP {
+		write, err := formatTMIOSymbol(op.WriteSymbol)
+		if err != nil {
+			return nil, nil, err
+		}
+		action.Content = append(action.Content, tmioString("write"), tmioString(write))
+	}
+	next := tmioNull()
+	if op.NextState != op.State {
+		next = tmioString(op.NextState.Name)
+	}
+	action.Content = append(action.Content, tmioString(direction), next)
+	return key, action, nil
+}
+
+func formatTMIOSymbol(s Symbol) (string, error) {
+	switch v := s.(type) {
+	case nil:
+		return tmioBlank, nil
+	case int:
+		if v >= 0 && v <= 9 {
+			return fmt.Sprint(v), nil
+		}
+	case string:
+		if utf8.RuneCountInString(v) == 1 && v != tmioBlank && v != ANY && v != KEEP {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("symbol %#v is not supported", s)
+}
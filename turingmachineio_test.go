@@ -0,0 +1,194 @@
+package turing_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTuringMachineIO(t *testing.T) {
+	right := turing.State{"right", false}
+	carry := turing.State{"carry", false}
+	done := turing.State{"done", true}
+
+	incrementOps := []turing.Op{
+		{right, "1", turing.KEEP, turing.RIGHT, right},
+		{right, "0", turing.KEEP, turing.RIGHT, right},
+		{right, nil, turing.KEEP, turing.LEFT, carry},
+		{carry, "1", "0", turing.LEFT, carry},
+		{carry, "0", "1", turing.LEFT, done},
+		{carry, nil, "1", turing.LEFT, done},
+	}
+
+	t.Run("Parse", func(t *testing.T) {
+		t.Log("should parse a turingmachine.io machine")
+
+		file, err := os.Open("testdata/binary_increment.yaml")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer file.Close()
+
+		def, err := turing.ParseTuringMachineIO(file)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, right, def.Start)
+		assert.ElementsMatch(t, incrementOps, def.Program.ListOps())
+		assert.Equal(t, []turing.Symbol{"1", "0", "1", "1"}, def.Tape)
+
+		machine := def.NewMachine()
+		if assert.NoError(t, machine.Run()) {
+			assert.Equal(t, "[0: 1]\n 1: 1\n 2: 0\n 3: 0\n", machine.Head.PrintTape(0, 3))
+		}
+	})
+
+	t.Run("ParseErrors", func(t *testing.T) {
+		t.Log("should report unsupported features and invalid machines")
+
+		tests := []struct {
+			yaml string
+			err  string
+		}{
+			{"start state: a\ntable:\n  a:\n", "missing blank symbol"},
+			{"blank: '__'\nstart state: a\ntable:\n  a:\n", `blank symbol "__" must be a single character`},
+			{"blank: ' '\nstart state: b\ntable:\n  a:\n", `start state: unknown state "b"`},
+			{"blank: ' '\nstart state: a\ntable:\n  a: R\n", "state a: transitions must be a map"},
+			{"blank: ' '\nstart state: a\ntable:\n  a:\n    1: S\n", `state a: unsupported action "S"`},
+			{"blank: ' '\nstart state: a\ntable:\n  a:\n    1: {write: 0}\n", "state a: action has no direction"},
+			{"blank: ' '\nstart state: a\ntable:\n  a:\n    1: {L: a, R: a}\n", "state a: action has more than one direction"},
+			{"blank: ' '\nstart state: a\ntable:\n  a:\n    1: {R: b}\n", `state a: unknown state "b"`},
+			{"blank: ' '\nstart state: a\ntable:\n  a:\n    10: R\n", `state a: symbol "10" must be a single character`},
+			{"blank: ' '\nstart state: a\ntable:\n  a:\n    1: [R]\n", "state a: invalid action [R]"},
+			{"table: [", "invalid turingmachine.io definition: yaml: line 1: did not find expected node content"},
+		}
+		for _, tt := range tests {
+			_, err := turing.ParseTuringMachineIO(strings.NewReader(tt.yaml))
+			assert.EqualError(t, err, tt.err, tt.yaml)
+		}
+	})
+
+	t.Run("Format", func(t *testing.T) {
+		t.Log("should format a machine, expanding ANY symbols")
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{right, turing.ANY, turing.KEEP, turing.RIGHT, right})
+		program.AddOp(turing.Op{right, nil, turing.KEEP, turing.LEFT, carry})
+		program.AddOp(turing.Op{carry, "1", "0", turing.LEFT, carry})
+		program.AddOp(turing.Op{carry, turing.ANY, "1", turing.LEFT, done})
+		def := turing.Definition{Program: &program, Start: right, Tape: []turing.Symbol{"1", nil, "1"}}
+
+		builder := strings.Builder{}
+		if !assert.NoError(t, turing.FormatTuringMachineIO(&builder, &def)) {
+			return
+		}
+		assert.Equal(t, ""+
+			"input: 1 1\n"+
+			"blank: ' '\n"+
+			"start state: right\n"+
+			"table:\n"+
+			"  carry:\n"+
+			"    \"1\":\n"+
+			"      write: \"0\"\n"+
+			"      L: null\n"+
+			"    ' ':\n"+
+			"      write: \"1\"\n"+
+			"      L: done\n"+
+			"    \"0\":\n"+
+			"      write: \"1\"\n"+
+			"      L: done\n"+
+			"  done: null\n"+
+			"  right:\n"+
+			"    ' ':\n"+
+			"      L: carry\n"+
+			"    \"1\": R\n"+
+			"    \"0\": R\n",
+			builder.String())
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Log("should parse a formatted machine to the same machine")
+
+		program := turing.Program{}
+		for _, op := range incrementOps {
+			program.AddOp(op)
+		}
+		def := turing.Definition{Program: &program, Start: right, Tape: []turing.Symbol{"1", "0"}}
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.FormatTuringMachineIO(&builder, &def)) {
+			decoded, err := turing.ParseTuringMachineIO(strings.NewReader(builder.String()))
+			if assert.NoError(t, err) {
+				assert.Equal(t, def.Start, decoded.Start)
+				assert.Equal(t, def.Tape, decoded.Tape)
+				assert.Equal(t, program.SortedOps(), decoded.Program.SortedOps())
+			}
+		}
+	})
+
+	t.Run("BooleanWords", func(t *testing.T) {
+		t.Log("should read y, n, yes, no, on and off as strings")
+
+		flip := turing.State{"flip", false}
+		on := turing.State{"on", true}
+		flipOps := []turing.Op{
+			{flip, "y", "n", turing.RIGHT, flip},
+			{flip, "n", "y", turing.RIGHT, flip},
+			{flip, nil, turing.KEEP, turing.LEFT, on},
+		}
+
+		def, err := turing.ParseTuringMachineIO(strings.NewReader("" +
+			"input: yyn\n" +
+			"blank: ' '\n" +
+			"start state: flip\n" +
+			"table:\n" +
+			"  flip:\n" +
+			"    y: {write: n, R}\n" +
+			"    n: {write: y, R}\n" +
+			"    ' ': {L: on}\n" +
+			"  on:\n"))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, flip, def.Start)
+		assert.ElementsMatch(t, flipOps, def.Program.ListOps())
+		assert.Equal(t, []turing.Symbol{"y", "y", "n"}, def.Tape)
+
+		builder := strings.Builder{}
+		if assert.NoError(t, turing.FormatTuringMachineIO(&builder, def)) {
+			decoded, err := turing.ParseTuringMachineIO(strings.NewReader(builder.String()))
+			if assert.NoError(t, err) {
+				assert.Equal(t, def.Tape, decoded.Tape)
+				assert.Equal(t, def.Program.SortedOps(), decoded.Program.SortedOps())
+			}
+		}
+	})
+
+	t.Run("FormatErrors", func(t *testing.T) {
+		t.Log("should not format machines turingmachine.io can't run")
+
+		state := turing.State{"state", false}
+		halt := turing.State{"halt", true}
+		tests := []struct {
+			ops []turing.Op
+			err string
+		}{
+			{[]turing.Op{{state, "1", "1", turing.STAY, state}}, "state state: stay movement is not supported"},
+			{[]turing.Op{{state, " ", "1", turing.LEFT, state}}, `state state: symbol " " is not supported`},
+			{[]turing.Op{{state, "1", "1", turing.LEFT, turing.State{"other", false}}}, "state other has no operations and is not halting"},
+			{[]turing.Op{{state, "1", "1", turing.LEFT, halt}, {halt, "1", "1", turing.LEFT, state}}, "halting state [halt] has operations"},
+		}
+		for _, tt := range tests {
+			program := turing.Program{}
+			for _, op := range tt.ops {
+				program.AddOp(op)
+			}
+			err := turing.FormatTuringMachineIO(&strings.Builder{}, &turing.Definition{Program: &program, Start: state})
+			assert.EqualError(t, err, tt.err)
+		}
+	})
+}
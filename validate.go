@@ -0,0 +1,175 @@
+package turing
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DiagnosticKind identifies a problem found on a program.
+type DiagnosticKind int
+
+const (
+	// UnknownMovement is an operation movement that is not LEFT, RIGHT or
+	// STAY. The head does not move for unknown movements.
+	UnknownMovement DiagnosticKind = iota
+	// UnreachableState is a state with operations that can't be reached from
+	// the start state.
+	UnreachableState
+	// DeadEndState is a non halting state without operations, the machine
+	// stops with an error when it reaches it.
+	DeadEndState
+	// KeepAsMatchSymbol is an operation that matches the KEEP symbol.
+	KeepAsMatchSymbol
+	// AnyAsWriteSymbol is an operation that writes the ANY symbol.
+	AnyAsWriteSymbol
+	// MissingStartState is a start state that is not set, or that is not
+	// halting and has no operations.
+	MissingStartState
+	// MissingProgram is a program that is not set, the machine can't run.
+	MissingProgram
+)
+
+var diagnosticKindNames = map[DiagnosticKind]string{
+	UnknownMovement:   "unknown movement",
+	UnreachableState:  "unreachable state",
+	DeadEndState:      "dead end state",
+	KeepAsMatchSymbol: "KEEP as match symbol",
+	AnyAsWriteSymbol:  "ANY as write symbol",
+	MissingStartState: "missing start state",
+	MissingProgram:    "missing program",
+}
+
+// String returns the kind description.
+func (k DiagnosticKind) String() string {
+	if name, ok := diagnosticKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("DiagnosticKind(%d)", int(k))
+}
+
+// Diagnostic is a problem found on a program.
+type Diagnostic struct {
+	// Kind is the problem found.
+	Kind DiagnosticKind
+	// State is the state with the problem.
+	State State
+	// Op is the operation with the problem, nil when the problem is on the
+	// state.
+	Op *Op
+}
+
+// String describes the problem.
+func (d Diagnostic) String() string {
+	switch d.Kind {
+	case UnknownMovement:
+		return fmt.Sprintf("op %v %v: unknown movement %q", d.Op.State, d.Op.Symbol, d.Op.Movement)
+	case KeepAsMatchSymbol:
+		return fmt.Sprintf("op %v %v: KEEP used as match symbol", d.Op.State, d.Op.Symbol)
+	case AnyAsWriteSymbol:
+		return fmt.Sprintf("op %v %v: ANY used as write symbol", d.Op.State, d.Op.Symbol)
+	case UnreachableState:
+		return fmt.Sprintf("state %v is unreachable from the start state", d.State)
+	case DeadEndState:
+		return fmt.Sprintf("state %v has no operations and is not halting", d.State)
+	case MissingStartState:
+		if d.State == (State{}) {
+			return "start state is not set"
+		}
+		return fmt.Sprintf("start state %v has no operations and is not halting", d.State)
+	case MissingProgram:
+		return "program is not set"
+	}
+	return fmt.Sprintf("state %v: %v", d.State, d.Kind)
+}
+
+// Validate checks the program before running it from the start state, and
+// returns the problems found, ordered by state name and symbol. A nil
+// program is only reported as missing.
+func Validate(p *Program, start State) []Diagnostic {
+	if p == nil {
+		return []Diagnostic{{Kind: MissingProgram, State: start}}
+	}
+
+	var diagnostics []Diagnostic
+
+	ops := p.SortedOps()
+	hasOps := make(map[State]bool)
+	for _, op := range ops {
+		hasOps[op.State] = true
+	}
+
+	startMissing := start == (State{}) || (!start.Halt && !hasOps[start])
+	if startMissing {
+		diagnostics = append(diagnostics, Diagnostic{Kind: MissingStartState, State: start})
+	}
+
+	deadEnds := make(map[State]bool)
+	for i := range ops {
+		op := &ops[i]
+		if !validMovement(op.Movement) {
+			diagnostics = append(diagnostics, Diagnostic{Kind: UnknownMovement, State: op.State, Op: op})
+		}
+		if op.Symbol == KEEP {
+			diagnostics = append(diagnostics, Diagnostic{Kind: KeepAsMatchSymbol, State: op.State, Op: op})
+		}
+		if op.WriteSymbol == ANY {
+			diagnostics = append(diagnostics, Diagnostic{Kind: AnyAsWriteSymbol, State: op.State, Op: op})
+		}
+		next := op.NextState
+		if !next.Halt && !hasOps[next] && !deadEnds[next] && !(startMissing && next == start) {
+			deadEnds[next] = true
+			diagnostics = append(diagnostics, Diagnostic{Kind: DeadEndState, State: next})
+		}
+	}
+
+	if !startMissing {
+		reachable := map[State]bool{start: true}
+		queue := []State{start}
+		next := make(map[State][]State)
+		for _, op := range ops {
+			next[op.State] = append(next[op.State], op.NextState)
+		}
+		for len(queue) > 0 {
+			state := queue[0]
+			queue = queue[1:]
+			for _, s := range next[state] {
+				if !reachable[s] {
+					reachable[s] = true
+					queue = append(queue, s)
+				}
+			}
+		}
+		for _, op := range ops {
+			if !reachable[op.State] {
+				reachable[op.State] = true
+				diagnostics = append(diagnostics, Diagnostic{Kind: UnreachableState, State: op.State})
+			}
+		}
+	}
+
+	sortDiagnostics(diagnostics)
+	return diagnostics
+}
+
+// Validate checks the definition program from its start state.
+func (d *Definition) Validate() []Diagnostic {
+	return Validate(d.Program, d.Start)
+}
+
+// sortDiagnostics orders the diagnostics by state, with the state
+// diagnostics before the operation ones.
+func sortDiagnostics(diagnostics []Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if a.State != b.State {
+			return compareStates(a.State, b.State) < 0
+		}
+		if (a.Op == nil) != (b.Op == nil) {
+			return a.Op == nil
+		}
+		if a.Op != nil && a.Op.Symbol != b.Op.Symbol {
+			return compareSymbols(a.Op.Symbol, b.Op.Symbol) < 0
+		}
+		return a.Kind < b.Kind
+	})
+}
@@ -0,0 +1,124 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/massahud/turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		t.Log("should not find problems on a valid program")
+
+		start, program := createSeparate01()
+
+		assert.Empty(t, turing.Validate(program, start))
+	})
+
+	t.Run("Problems", func(t *testing.T) {
+		t.Log("should find all problems, ordered by state and symbol")
+
+		start := turing.State{"start", false}
+		loop := turing.State{"loop", false}
+		lost := turing.State{"lost", false}
+		orphan := turing.State{"orphan", false}
+		halt := turing.State{"halt", true}
+
+		typo := turing.Op{start, 0, 1, "rigth", loop}
+		keep := turing.Op{start, turing.KEEP, 1, turing.LEFT, halt}
+		anyWrite := turing.Op{loop, 1, turing.ANY, turing.LEFT, start}
+		toLost := turing.Op{loop, 0, 0, turing.LEFT, lost}
+		fromOrphan := turing.Op{orphan, nil, nil, turing.STAY, lost}
+
+		program := turing.Program{}
+		program.AddOp(typo)
+		program.AddOp(keep)
+		program.AddOp(anyWrite)
+		program.AddOp(toLost)
+		program.AddOp(fromOrphan)
+
+		diagnostics := turing.Validate(&program, start)
+
+		assert.Equal(t, []turing.Diagnostic{
+			{Kind: turing.AnyAsWriteSymbol, State: loop, Op: &anyWrite},
+			{Kind: turing.DeadEndState, State: lost},
+			{Kind: turing.UnreachableState, State: orphan},
+			{Kind: turing.UnknownMovement, State: start, Op: &typo},
+			{Kind: turing.KeepAsMatchSymbol, State: start, Op: &keep},
+		}, diagnostics)
+
+		messages := make([]string, len(diagnostics))
+		for i, d := range diagnostics {
+			messages[i] = d.String()
+		}
+		assert.Equal(t, []string{
+			"op loop 1: ANY used as write symbol",
+			"state lost has no operations and is not halting",
+			"state orphan is unreachable from the start state",
+			`op start 0: unknown movement "rigth"`,
+			"op start __turing[keep]: KEEP used as match symbol",
+		}, messages)
+	})
+
+	t.Run("MissingStart", func(t *testing.T) {
+		t.Log("should find missing start states")
+
+		state := turing.State{"state", false}
+		other := turing.State{"other", false}
+
+		program := turing.Program{}
+		program.AddOp(turing.Op{state, 0, 0, turing.LEFT, state})
+
+		diagnostics := turing.Validate(&program, turing.State{})
+		if assert.Len(t, diagnostics, 1) {
+			assert.Equal(t, turing.MissingStartState, diagnostics[0].Kind)
+			assert.Equal(t, "start state is not set", diagnostics[0].String())
+		}
+
+		diagnostics = turing.Validate(&program, other)
+		if assert.Len(t, diagnostics, 1) {
+			assert.Equal(t, turing.MissingStartState, diagnostics[0].Kind)
+			assert.Equal(t, "start state other has no operations and is not halting", diagnostics[0].String())
+		}
+
+		assert.Empty(t, turing.Validate(&turing.Program{}, turing.State{"halt", true}))
+	})
+
+	t.Run("MissingProgram", func(t *testing.T) {
+		t.Log("should report a nil program instead of panicking")
+
+		start := turing.State{"start", false}
+		diagnostics := turing.Validate(nil, start)
+		if assert.Len(t, diagnostics, 1) {
+			assert.Equal(t, turing.Diagnostic{Kind: turing.MissingProgram, State: start}, diagnostics[0])
+			assert.Equal(t, "program is not set", diagnostics[0].String())
+		}
+
+		def := turing.Definition{Start: start}
+		assert.Equal(t, diagnostics, def.Validate())
+	})
+
+	t.Run("Definition", func(t *testing.T) {
+		t.Log("should validate a definition from its start state")
+
+		start, program := createSeparate01()
+		def := turing.Definition{Program: program, Start: turing.State{"back1", false}}
+
+		diagnostics := def.Validate()
+		if assert.Len(t, diagnostics, 3) {
+			assert.Equal(t, turing.Diagnostic{Kind: turing.UnreachableState, State: turing.State{"back0", false}}, diagnostics[0])
+			assert.Equal(t, turing.Diagnostic{Kind: turing.UnreachableState, State: turing.State{"get0", false}}, diagnostics[1])
+			assert.Equal(t, turing.Diagnostic{Kind: turing.UnreachableState, State: start}, diagnostics[2])
+		}
+	})
+
+	t.Run("KindString", func(t *testing.T) {
+		t.Log("should describe the diagnostic kinds")
+
+		assert.Equal(t, "unknown movement", turing.UnknownMovement.String())
+		assert.Equal(t, "missing start state", turing.MissingStartState.String())
+		assert.Equal(t, "missing program", turing.MissingProgram.String())
+		assert.Equal(t, "DiagnosticKind(42)", turing.DiagnosticKind(42).String())
+	})
+}
@@ -1,47 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"syscall/js"
 
 	"github.com/massahud/turing"
+	"github.com/massahud/turing/machines"
 )
 
-// createSeparate01 creates a simple program that separates 0's from 1's
-// of the given sequence.
-//
-// It returns the start state and the program.
-func createSeparate01() (turing.State, *turing.Program) {
-	get1 := turing.State{Name: "get1"}
-	get0 := turing.State{Name: "get0"}
-	back0 := turing.State{Name: "back0"}
-	back1 := turing.State{Name: "back1"}
-	halt := turing.State{Name: "halt", Halt: true}
-
-	b0 := byte(0)
-	b1 := byte(1)
-
-	program := turing.Program{}
-	program.AddOp(turing.Op{State: get1, Symbol: b1, WriteSymbol: nil, Movement: turing.RIGHT, NextState: get0})
-	program.AddOp(turing.Op{State: get1, Symbol: b0, WriteSymbol: b0, Movement: turing.RIGHT, NextState: get1})
-	program.AddOp(turing.Op{State: get1, Symbol: nil, WriteSymbol: nil, Movement: turing.STAY, NextState: halt})
-	program.AddOp(turing.Op{State: get0, Symbol: b1, WriteSymbol: b1, Movement: turing.RIGHT, NextState: get0})
-	program.AddOp(turing.Op{State: get0, Symbol: b0, WriteSymbol: b1, Movement: turing.LEFT, NextState: back0})
-	program.AddOp(turing.Op{State: get0, Symbol: nil, WriteSymbol: nil, Movement: turing.LEFT, NextState: back1})
-	program.AddOp(turing.Op{State: back0, Symbol: turing.ANY, WriteSymbol: turing.KEEP, Movement: turing.LEFT, NextState: back0})
-	program.AddOp(turing.Op{State: back0, Symbol: nil, WriteSymbol: b0, Movement: turing.RIGHT, NextState: get1})
-	program.AddOp(turing.Op{State: back1, Symbol: turing.ANY, WriteSymbol: turing.KEEP, Movement: turing.LEFT, NextState: back1})
-	program.AddOp(turing.Op{State: back1, Symbol: nil, WriteSymbol: b1, Movement: turing.STAY, NextState: halt})
-
-	return get1, &program
-}
-
 func main() {
 
-	head := turing.Head{}
-	start, program := createSeparate01()
-	machine := turing.Machine{Head: &head, Program: program, State: start}
+	separate := machines.SeparateZeroOne()
+	computation := turing.Computation{
+		Program:    separate.Program,
+		Start:      separate.Start,
+		Input:      turing.Bytes,
+		Convention: turing.HeadBlock,
+		MaxSteps:   turing.DefaultComputeSteps,
+	}
 
 	exit := make(chan struct{})
 
@@ -58,35 +36,34 @@ func main() {
 		byteSequence := make([]byte, jsArr.Length(), jsArr.Length())
 		js.CopyBytesToGo(byteSequence, jsArr)
 
-		sequence := make([]turing.Symbol, len(byteSequence), len(byteSequence))
-		for i := range byteSequence {
-			sequence[i] = byteSequence[i]
+		machine, err := computation.NewMachine(byteSequence)
+		if err != nil {
+			return fmt.Sprint("ERROR: error creating machine:", err.Error())
 		}
+		head := machine.Head
 
 		builder := strings.Builder{}
-		builder.WriteString(fmt.Sprintf("Running for sequence: %v\n\n", sequence))
-
-		tape := turing.NewInfiniteTape()
-		tape.Set(0, sequence...)
-
-		head.Attach(tape, 0)
+		builder.WriteString(fmt.Sprintf("Running for sequence: %v\n\n", byteSequence))
 
-		builder.WriteString(fmt.Sprintln("Initial configuration:", len(sequence)))
-		builder.WriteString(head.PrintTape(0, len(sequence)))
+		builder.WriteString(fmt.Sprintln("Initial configuration:", len(byteSequence)))
+		builder.WriteString(head.PrintTape(0, len(byteSequence)))
 
-		err := machine.Run()
+		result, err := machine.RunContext(context.Background(), computation.MaxSteps)
 		if err != nil {
 			return fmt.Sprint("ERROR: error executing machine:", err.Error())
 		}
+		if result.Reason != turing.Halted {
+			return fmt.Sprintf("ERROR: machine did not halt after %d steps", result.Steps)
+		}
 
-		for i := range byteSequence {
-			v, _ := tape.Get(i)
-			byteSequence[i] = v.(byte)
+		output, err := computation.Decode(machine)
+		if err != nil {
+			return fmt.Sprint("ERROR: error reading output:", err.Error())
 		}
-		js.CopyBytesToJS(jsArr, byteSequence)
+		js.CopyBytesToJS(jsArr, output.([]byte))
 
 		builder.WriteString("After execution:\n")
-		builder.WriteString(head.PrintTape(0, len(sequence)))
+		builder.WriteString(head.PrintTape(0, len(byteSequence)))
 		return builder.String()
 	}))
 